@@ -1,34 +1,35 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"os"
-	"os/exec"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/fatih/color"
-	"github.com/hashicorp/go-plugin"
 	"github.com/rogerwelin/cassowary/pkg/client"
+	"github.com/rogerwelin/cassowary/pkg/client/scenario"
+	"github.com/rogerwelin/cassowary/pkg/cluster"
 	"github.com/urfave/cli/v2"
-
-	hclog "github.com/hashicorp/go-hclog"
 )
 
 var (
-	version             = "dev"
-	errConcurrencyLevel = errors.New("Error: Concurrency level cannot be set to: 0")
-	errRequestNo        = errors.New("Error: No. of request cannot be set to: 0")
-	errNotValidURL      = errors.New("Error: Not a valid URL. Must have the following format: http{s}://{host}")
-	errNotValidHeader   = errors.New("Error: Not a valid header value. Did you forget : ?")
-	errDurationValue    = errors.New("Error: Duration cannot be set to 0 or negative")
+	version                = "dev"
+	errConcurrencyLevel    = errors.New("Error: Concurrency level cannot be set to: 0")
+	errRequestNo           = errors.New("Error: No. of request cannot be set to: 0")
+	errNotValidURL         = errors.New("Error: Not a valid URL. Must have the following format: http{s}://{host}")
+	errNotValidHeader      = errors.New("Error: Not a valid header value. Did you forget : ?")
+	errDurationValue       = errors.New("Error: Duration cannot be set to 0 or negative")
+	errScenarioFile        = errors.New("Error: No scenario file specified")
+	errWorkerToken         = errors.New("Error: --token is required")
+	errNotValidOTLPHeaders = errors.New("Error: --otlp-headers must be a comma-separated list of key=value pairs")
 )
 
 func outPutResults(metrics client.ResultMetrics) {
@@ -49,53 +50,208 @@ func outPutResults(metrics client.ResultMetrics) {
 	)
 }
 
-func outPutJSON(fileName string, metrics client.ResultMetrics) error {
-	if fileName == "" {
-		// default filename for json metrics output.
-		fileName = "out.json"
+func outPutWebSocketResults(metrics client.ResultMetrics) {
+	ws := metrics.WebSocketStats
+	printf(wsSummaryTable,
+		color.CyanString(fmt.Sprintf("%.2f", ws.HandshakeMean)),
+		color.CyanString(fmt.Sprintf("%.2f", ws.TimeToFirstMessage)),
+		color.CyanString(fmt.Sprintf("%.2f", ws.MessageRTTMean)),
+		color.CyanString(fmt.Sprintf("%.2f", ws.MessageRTTMedian)),
+		color.CyanString(fmt.Sprintf("%.2f", ws.MessageRTT95p)),
+		color.CyanString(strconv.Itoa(metrics.TotalRequests)),
+	)
+}
+
+func outPutHTTP2Results(metrics client.ResultMetrics) {
+	h2 := metrics.HTTP2Stats
+	printf(h2SummaryTable,
+		color.CyanString(fmt.Sprintf("%.2f", h2.ConnectionSetupMean)),
+		color.CyanString(fmt.Sprintf("%.2f", h2.StreamLatencyMean)),
+		color.CyanString(fmt.Sprintf("%.2f", h2.StreamLatencyMedian)),
+		color.CyanString(fmt.Sprintf("%.2f", h2.StreamLatency95p)),
+		color.CyanString(fmt.Sprintf("%.2f", h2.StreamsPerConn)),
+		color.CyanString(strconv.Itoa(metrics.TotalRequests)),
+		color.CyanString(fmt.Sprintf("%.2f", metrics.RequestsPerSecond)),
+	)
+}
+
+func outPutScenarioResults(metrics client.ResultMetrics) {
+	for _, step := range metrics.ScenarioStats.Steps {
+		printf(scenarioStepLine,
+			step.Name,
+			color.CyanString(fmt.Sprintf("%.2f", step.Mean)),
+			color.CyanString(fmt.Sprintf("%.2f", step.Median)),
+			color.CyanString(fmt.Sprintf("%.2f", step.P95)),
+			color.CyanString(strconv.Itoa(step.Failed)),
+		)
 	}
-	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return err
+	printf(scenarioSummaryTable,
+		color.CyanString(strconv.Itoa(metrics.TotalRequests)),
+		color.CyanString(strconv.Itoa(metrics.FailedRequests)),
+		color.CyanString(fmt.Sprintf("%.2f", metrics.RequestsPerSecond)),
+	)
+}
+
+// exportMetrics fans the run's final metrics out to every sink configured
+// on c (JSON/Prometheus/CloudWatch/OTLP), composing freely rather than
+// picking just one.
+func exportMetrics(c *client.Cassowary, metrics client.ResultMetrics) error {
+	for _, sink := range c.MetricSinks() {
+		if err := sink.Export(context.Background(), metrics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamEvents wires c.Events up to every StreamingSink in c.ExtraSinks
+// (e.g. an OTLPSink emitting per-request spans) for the duration of a
+// run. It returns a func that closes the channel and waits for every
+// sink to finish draining it; call it once the run's Coordinate* call
+// returns, before exporting the final aggregate metrics.
+func streamEvents(c *client.Cassowary) func() {
+	var streaming []client.StreamingSink
+	for _, sink := range c.ExtraSinks {
+		if s, ok := sink.(client.StreamingSink); ok {
+			streaming = append(streaming, s)
+		}
+	}
+	if len(streaming) == 0 {
+		return func() {}
+	}
+
+	events := make(chan client.RequestResult, 100)
+	c.Events = events
+
+	var wg sync.WaitGroup
+	for _, sink := range streaming {
+		wg.Add(1)
+		go func(sink client.StreamingSink) {
+			defer wg.Done()
+			sink.Stream(context.Background(), events)
+		}(sink)
 	}
-	defer f.Close()
 
-	enc := json.NewEncoder(f)
-	return enc.Encode(metrics)
+	return func() {
+		close(events)
+		wg.Wait()
+	}
 }
 
 func runLoadTest(c *client.Cassowary) error {
+	stop := streamEvents(c)
 	metrics, err := c.Coordinate()
+	stop()
 	if err != nil {
 		return err
 	}
 	outPutResults(metrics)
+	return exportMetrics(c, metrics)
+}
+
+func runWebSocketLoadTest(c *client.Cassowary) error {
+	stop := streamEvents(c)
+	metrics, err := c.CoordinateWebSocket()
+	stop()
+	if err != nil {
+		return err
+	}
+	outPutWebSocketResults(metrics)
+	return exportMetrics(c, metrics)
+}
+
+func runHTTP2LoadTest(c *client.Cassowary) error {
+	stop := streamEvents(c)
+	metrics, err := c.CoordinateHTTP2()
+	stop()
+	if err != nil {
+		return err
+	}
+	outPutHTTP2Results(metrics)
+	return exportMetrics(c, metrics)
+}
 
-	if c.ExportMetrics {
-		return outPutJSON(c.ExportMetricsFile, metrics)
+func runScenarioLoadTest(c *client.Cassowary, scn *scenario.Scenario) error {
+	stop := streamEvents(c)
+	metrics, err := c.CoordinateScenario(scn)
+	stop()
+	if err != nil {
+		return err
 	}
+	outPutScenarioResults(metrics)
+	return exportMetrics(c, metrics)
+}
 
-	if c.PromExport {
-		err := c.PushPrometheusMetrics(metrics)
+func buildTLSConfig(c *cli.Context) (*tls.Config, error) {
+	tlsConfig := new(tls.Config)
+	if c.String("ca") != "" {
+		pemCerts, err := ioutil.ReadFile(c.String("ca"))
 		if err != nil {
-			return err
+			return nil, err
 		}
+		ca := x509.NewCertPool()
+		if !ca.AppendCertsFromPEM(pemCerts) {
+			return nil, fmt.Errorf("failed to read CA from PEM")
+		}
+		tlsConfig.RootCAs = ca
 	}
 
-	if c.Cloudwatch {
-		session, err := session.NewSession()
+	if c.String("cert") != "" && c.String("key") != "" {
+		cert, err := tls.LoadX509KeyPair(c.String("cert"), c.String("key"))
 		if err != nil {
-			return err
+			return nil, err
 		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
 
-		svc := cloudwatch.New(session)
-		_, err = c.PutCloudwatchMetrics(svc, metrics)
-		if err != nil {
-			return err
+	return tlsConfig, nil
+}
+
+func buildPluginManager(c *cli.Context) (*client.PluginManager, error) {
+	if c.String("plugin-dir") == "" {
+		return nil, nil
+	}
+	return client.NewPluginManager(c.String("plugin-dir"), c.Bool("strict-plugins"))
+}
+
+func buildRetryPolicy(c *cli.Context) client.RetryPolicy {
+	var retryOn []string
+	if c.String("retry-on") != "" {
+		retryOn = strings.Split(c.String("retry-on"), ",")
+	}
+
+	return client.RetryPolicy{
+		MaxRetries:  c.Int("retry-max"),
+		RetryOn:     retryOn,
+		BackoffBase: c.Duration("retry-backoff-base"),
+		BackoffMax:  c.Duration("retry-backoff-max"),
+		Jitter:      c.Bool("retry-jitter"),
+	}
+}
+
+// buildOTLPSink constructs an OTLPSink from --otlp-* flags, or returns a
+// nil sink if --otlp-endpoint wasn't set.
+func buildOTLPSink(c *cli.Context) (client.MetricSink, error) {
+	if c.String("otlp-endpoint") == "" {
+		return nil, nil
+	}
+
+	headers := make(map[string]string)
+	if raw := c.String("otlp-headers"); raw != "" {
+		for _, pair := range strings.Split(raw, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return nil, errNotValidOTLPHeaders
+			}
+			headers[kv[0]] = kv[1]
 		}
 	}
 
-	return nil
+	return client.NewOTLPSink(client.OTLPConfig{
+		Endpoint: c.String("otlp-endpoint"),
+		Headers:  headers,
+		Insecure: c.Bool("otlp-insecure"),
+	})
 }
 
 func validateCLI(c *cli.Context) error {
@@ -176,25 +332,23 @@ func validateCLI(c *cli.Context) error {
 		httpMethod = "GET"
 	}
 
-	tlsConfig := new(tls.Config)
-	if c.String("ca") != "" {
-		pemCerts, err := ioutil.ReadFile(c.String("ca"))
-		if err != nil {
-			return err
-		}
-		ca := x509.NewCertPool()
-		if !ca.AppendCertsFromPEM(pemCerts) {
-			return fmt.Errorf("failed to read CA from PEM")
-		}
-		tlsConfig.RootCAs = ca
+	tlsConfig, err := buildTLSConfig(c)
+	if err != nil {
+		return err
 	}
 
-	if c.String("cert") != "" && c.String("key") != "" {
-		cert, err := tls.LoadX509KeyPair(c.String("cert"), c.String("key"))
-		if err != nil {
-			return err
-		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+	plugins, err := buildPluginManager(c)
+	if err != nil {
+		return err
+	}
+
+	var extraSinks []client.MetricSink
+	otlpSink, err := buildOTLPSink(c)
+	if err != nil {
+		return err
+	}
+	if otlpSink != nil {
+		extraSinks = append(extraSinks, otlpSink)
 	}
 
 	cass := &client.Cassowary{
@@ -215,174 +369,764 @@ func validateCLI(c *cli.Context) error {
 		HTTPMethod:        httpMethod,
 		URLPaths:          urlSuffixes,
 		Data:              data,
+		Plugins:           plugins,
+		StrictPlugins:     c.Bool("strict-plugins"),
+		RetryPolicy:       buildRetryPolicy(c),
+		ExtraSinks:        extraSinks,
 	}
 
 	return runLoadTest(cass)
 }
 
-func runCLI(args []string) {
-	app := cli.NewApp()
-	app.Name = "cassowary - 學名"
-	app.HelpName = "cassowary"
-	app.UsageText = "cassowary [command] [command options] [arguments...]"
-	app.EnableBashCompletion = true
-	app.Usage = ""
-	app.Version = version
-	app.Commands = []*cli.Command{
-		{
-			Name:  "run",
-			Usage: "start load-test",
-			Flags: []cli.Flag{
-				&cli.StringFlag{
-					Name:     "u",
-					Aliases:  []string{"url"},
-					Usage:    "the url (absoluteURI) to be used",
-					Required: true,
-				},
-				&cli.IntFlag{
-					Name:    "c",
-					Aliases: []string{"concurrency"},
-					Usage:   "number of concurrent users",
-					Value:   1,
-				},
-				&cli.IntFlag{
-					Name:    "n",
-					Aliases: []string{"requests"},
-					Usage:   "number of requests to perform",
-					Value:   1,
-				},
-				&cli.StringFlag{
-					Name:    "f",
-					Aliases: []string{"file"},
-					Usage:   "file-slurp mode: specify `FILE` path, local or www, containing the url suffixes",
-				},
-				&cli.StringFlag{
-					Name:    "d",
-					Aliases: []string{"duration"},
-					Usage:   "set the duration in seconds of the load test (example: do 100 requests in a duration of 30s)",
-				},
-				&cli.IntFlag{
-					Name:    "t",
-					Aliases: []string{"timeout"},
-					Usage:   "http client timeout",
-					Value:   5,
-				},
-				&cli.StringFlag{
-					Name:    "p",
-					Aliases: []string{"prompushgwurl"},
-					Usage:   "specify prometheus push gateway url to send metrics (optional)",
-				},
-				&cli.BoolFlag{
-					Name:    "C",
-					Aliases: []string{"cloudwatch"},
-					Usage:   "enable to send metrics to AWS Cloudwatch",
-				},
-				&cli.StringFlag{
-					Name:    "H",
-					Aliases: []string{"header"},
-					Usage:   "add arbitrary header, eg. 'Host: www.example.com'",
-				},
-				&cli.BoolFlag{
-					Name:    "F",
-					Aliases: []string{"json-metrics"},
-					Usage:   "outputs metrics to a json file by setting flag to true",
-				},
-				&cli.StringFlag{
-					Name:  "postfile",
-					Usage: "file containing data to POST (content type will default to application/json)",
-				},
-				&cli.StringFlag{
-					Name:  "patchfile",
-					Usage: "file containing data to PATCH (content type will default to application/json)",
-				},
-				&cli.StringFlag{
-					Name:  "putfile",
-					Usage: "file containing data to PUT (content type will default to application/json)",
-				},
-				&cli.StringFlag{
-					Name:  "json-metrics-file",
-					Usage: "outputs metrics to a custom json filepath, if json-metrics is set to true",
-				},
-				&cli.BoolFlag{
-					Name:  "disable-keep-alive",
-					Usage: "use this flag to disable http keep-alive",
-				},
-				&cli.StringFlag{
-					Name:  "ca",
-					Usage: "ca certificate to verify peer against",
-				},
-				&cli.StringFlag{
-					Name:  "cert",
-					Usage: "client authentication certificate",
-				},
-				&cli.StringFlag{
-					Name:  "key",
-					Usage: "client authentication key",
-				},
-			},
-			Action: validateCLI,
-		},
+func validateCLIWebSocket(c *cli.Context) error {
+	var header []string
+
+	if c.Int("concurrency") == 0 {
+		return errConcurrencyLevel
 	}
 
-	if err := initPlugins(); err != nil {
-		log.Fatalf("error initializing plugins: %s\n", err)
+	if !client.IsValidURL(c.String("url")) {
+		return errNotValidURL
 	}
-	err := app.Run(args)
-	if err != nil {
-		log.Fatalf("error running application: %s\n", err)
+
+	if c.String("header") != "" {
+		length := 0
+		length, header = client.SplitHeader(c.String("header"))
+		if length != 2 {
+			return errNotValidHeader
+		}
 	}
-}
 
-func initPlugins() error {
-	// Create an hclog.Logger
-	logger := hclog.New(&hclog.LoggerOptions{
-		Name:   "plugin",
-		Output: os.Stdout,
-		Level:  hclog.Debug,
-	})
+	duration := 0
+	if c.String("duration") != "" {
+		var err error
+		duration, err = strconv.Atoi(c.String("duration"))
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return errDurationValue
+		}
+	}
 
-	// From example in docs:
-	// handshakeConfigs are used to just do a basic handshake between
-	// a plugin and host. If the handshake fails, a user friendly error is shown.
-	// This prevents users from executing bad plugins or executing a plugin
-	// directory. It is a UX feature, not a security feature.
+	tlsConfig, err := buildTLSConfig(c)
+	if err != nil {
+		return err
+	}
 
-	handshakeConfig := plugin.HandshakeConfig{
-		ProtocolVersion:  1,
-		MagicCookieKey:   "BASIC_PLUGIN",
-		MagicCookieValue: "hello",
+	plugins, err := buildPluginManager(c)
+	if err != nil {
+		return err
 	}
 
-	pluginMap := map[string]plugin.Plugin{
-		"plugin": &client.PluginImpl{},
+	cass := &client.Cassowary{
+		BaseURL:           c.String("url"),
+		ConcurrencyLevel:  c.Int("concurrency"),
+		RequestHeader:     header,
+		TLSConfig:         tlsConfig,
+		Duration:          duration,
+		PromExport:        c.String("prompushgwurl") != "",
+		PromURL:           c.String("prompushgwurl"),
+		Cloudwatch:        c.Bool("cloudwatch"),
+		ExportMetrics:     c.Bool("json-metrics"),
+		ExportMetricsFile: c.String("json-metrics-file"),
+		Timeout:           c.Int("timeout"),
+		WSMessageFile:     c.String("message-file"),
+		WSMessageCount:    c.Int("message-count"),
+		WSSendInterval:    c.Duration("send-interval"),
+		Plugins:           plugins,
+		StrictPlugins:     c.Bool("strict-plugins"),
 	}
 
-	c := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig: handshakeConfig,
-		Plugins:         pluginMap,
-		Cmd:             exec.Command("./plugin/plugin"),
-		Logger:          logger,
-	})
-	defer c.Kill()
+	return runWebSocketLoadTest(cass)
+}
 
-	// Connect via RPC
-	rpcClient, err := c.Client()
-	if err != nil {
-		return fmt.Errorf("could not build rpc client: %s", err)
-	}
+func validateCLIHTTP2(c *cli.Context) error {
+	var header []string
 
-	// Request the plugin
-	pluginName := "plugin"
-	raw, err := rpcClient.Dispense(pluginName)
-	if err != nil {
-		return fmt.Errorf("could not dispense rpc request to %s: %s", pluginName, err)
+	if c.Int("concurrency") == 0 {
+		return errConcurrencyLevel
 	}
 
-	// We should have a Greeter now! This feels like a normal interface
-	// implementation but is in fact over an RPC connection.
-	plugin := raw.(client.Plugin)
-	if errString := plugin.Init(); errString != "" {
-		return fmt.Errorf("plugin returned error: %s", errString)
+	if c.Int("requests") == 0 {
+		return errRequestNo
+	}
+
+	if !client.IsValidURL(c.String("url")) {
+		return errNotValidURL
+	}
+
+	if c.String("header") != "" {
+		length := 0
+		length, header = client.SplitHeader(c.String("header"))
+		if length != 2 {
+			return errNotValidHeader
+		}
+	}
+
+	duration := 0
+	if c.String("duration") != "" {
+		var err error
+		duration, err = strconv.Atoi(c.String("duration"))
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return errDurationValue
+		}
+	}
+
+	tlsConfig, err := buildTLSConfig(c)
+	if err != nil {
+		return err
+	}
+
+	plugins, err := buildPluginManager(c)
+	if err != nil {
+		return err
+	}
+
+	cass := &client.Cassowary{
+		BaseURL:           c.String("url"),
+		ConcurrencyLevel:  c.Int("concurrency"),
+		Requests:          c.Int("requests"),
+		RequestHeader:     header,
+		TLSConfig:         tlsConfig,
+		Duration:          duration,
+		PromExport:        c.String("prompushgwurl") != "",
+		PromURL:           c.String("prompushgwurl"),
+		Cloudwatch:        c.Bool("cloudwatch"),
+		ExportMetrics:     c.Bool("json-metrics"),
+		ExportMetricsFile: c.String("json-metrics-file"),
+		Timeout:           c.Int("timeout"),
+		HTTPMethod:        "GET",
+		Plugins:           plugins,
+		StrictPlugins:     c.Bool("strict-plugins"),
+	}
+
+	return runHTTP2LoadTest(cass)
+}
+
+func validateCLIScenario(c *cli.Context) error {
+	if c.String("file") == "" {
+		return errScenarioFile
+	}
+
+	if c.Int("concurrency") == 0 {
+		return errConcurrencyLevel
+	}
+
+	duration := 0
+	if c.String("duration") != "" {
+		var err error
+		duration, err = strconv.Atoi(c.String("duration"))
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return errDurationValue
+		}
+	}
+
+	scn, err := scenario.Parse(c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := buildTLSConfig(c)
+	if err != nil {
+		return err
+	}
+
+	plugins, err := buildPluginManager(c)
+	if err != nil {
+		return err
+	}
+
+	cass := &client.Cassowary{
+		BaseURL:           scn.Name,
+		ConcurrencyLevel:  c.Int("concurrency"),
+		Requests:          c.Int("requests"),
+		Duration:          duration,
+		TLSConfig:         tlsConfig,
+		PromExport:        c.String("prompushgwurl") != "",
+		PromURL:           c.String("prompushgwurl"),
+		Cloudwatch:        c.Bool("cloudwatch"),
+		ExportMetrics:     c.Bool("json-metrics"),
+		ExportMetricsFile: c.String("json-metrics-file"),
+		Timeout:           c.Int("timeout"),
+		Plugins:           plugins,
+		StrictPlugins:     c.Bool("strict-plugins"),
+	}
+
+	return runScenarioLoadTest(cass, scn)
+}
+
+func validateCLICoordinator(c *cli.Context) error {
+	if c.Int("concurrency") == 0 {
+		return errConcurrencyLevel
+	}
+
+	if c.Int("requests") == 0 && c.String("duration") == "" {
+		return errRequestNo
+	}
+
+	if !client.IsValidURL(c.String("url")) {
+		return errNotValidURL
+	}
+
+	if c.String("token") == "" {
+		return errWorkerToken
+	}
+
+	duration := 0
+	if c.String("duration") != "" {
+		var err error
+		duration, err = strconv.Atoi(c.String("duration"))
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return errDurationValue
+		}
+	}
+
+	var header []string
+	if c.String("header") != "" {
+		length := 0
+		length, header = client.SplitHeader(c.String("header"))
+		if length != 2 {
+			return errNotValidHeader
+		}
+	}
+
+	coord := cluster.NewCoordinator(c.String("listen"), c.String("token"))
+	if err := coord.Listen(); err != nil {
+		return err
+	}
+
+	fmt.Printf("waiting %ds for workers to register on %s...\n", c.Int("wait"), c.String("listen"))
+	time.Sleep(time.Duration(c.Int("wait")) * time.Second)
+
+	metrics, err := coord.Run(cluster.JobSpec{
+		BaseURL:          c.String("url"),
+		ConcurrencyLevel: c.Int("concurrency"),
+		Requests:         c.Int("requests"),
+		Duration:         duration,
+		Timeout:          c.Int("timeout"),
+		RequestHeader:    header,
+	})
+	if err != nil {
+		return err
+	}
+
+	outPutResults(metrics)
+
+	cass := &client.Cassowary{
+		PromExport:        c.String("prompushgwurl") != "",
+		PromURL:           c.String("prompushgwurl"),
+		Cloudwatch:        c.Bool("cloudwatch"),
+		ExportMetrics:     c.Bool("json-metrics"),
+		ExportMetricsFile: c.String("json-metrics-file"),
+	}
+
+	return exportMetrics(cass, metrics)
+}
+
+func validateCLIWorker(c *cli.Context) error {
+	if c.String("token") == "" {
+		return errWorkerToken
+	}
+
+	worker := cluster.NewWorker(c.String("coordinator"), c.String("listen"), c.String("token"))
+	return worker.Run()
+}
+
+func runCLI(args []string) {
+	app := cli.NewApp()
+	app.Name = "cassowary - 學名"
+	app.HelpName = "cassowary"
+	app.UsageText = "cassowary [command] [command options] [arguments...]"
+	app.EnableBashCompletion = true
+	app.Usage = ""
+	app.Version = version
+	app.Commands = []*cli.Command{
+		{
+			Name:  "run",
+			Usage: "start load-test",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "u",
+					Aliases:  []string{"url"},
+					Usage:    "the url (absoluteURI) to be used",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:    "c",
+					Aliases: []string{"concurrency"},
+					Usage:   "number of concurrent users",
+					Value:   1,
+				},
+				&cli.IntFlag{
+					Name:    "n",
+					Aliases: []string{"requests"},
+					Usage:   "number of requests to perform",
+					Value:   1,
+				},
+				&cli.StringFlag{
+					Name:    "f",
+					Aliases: []string{"file"},
+					Usage:   "file-slurp mode: specify `FILE` path, local or www, containing the url suffixes",
+				},
+				&cli.StringFlag{
+					Name:    "d",
+					Aliases: []string{"duration"},
+					Usage:   "set the duration in seconds of the load test (example: do 100 requests in a duration of 30s)",
+				},
+				&cli.IntFlag{
+					Name:    "t",
+					Aliases: []string{"timeout"},
+					Usage:   "http client timeout",
+					Value:   5,
+				},
+				&cli.StringFlag{
+					Name:    "p",
+					Aliases: []string{"prompushgwurl"},
+					Usage:   "specify prometheus push gateway url to send metrics (optional)",
+				},
+				&cli.BoolFlag{
+					Name:    "C",
+					Aliases: []string{"cloudwatch"},
+					Usage:   "enable to send metrics to AWS Cloudwatch",
+				},
+				&cli.StringFlag{
+					Name:    "H",
+					Aliases: []string{"header"},
+					Usage:   "add arbitrary header, eg. 'Host: www.example.com'",
+				},
+				&cli.BoolFlag{
+					Name:    "F",
+					Aliases: []string{"json-metrics"},
+					Usage:   "outputs metrics to a json file by setting flag to true",
+				},
+				&cli.StringFlag{
+					Name:  "postfile",
+					Usage: "file containing data to POST (content type will default to application/json)",
+				},
+				&cli.StringFlag{
+					Name:  "patchfile",
+					Usage: "file containing data to PATCH (content type will default to application/json)",
+				},
+				&cli.StringFlag{
+					Name:  "putfile",
+					Usage: "file containing data to PUT (content type will default to application/json)",
+				},
+				&cli.StringFlag{
+					Name:  "json-metrics-file",
+					Usage: "outputs metrics to a custom json filepath, if json-metrics is set to true",
+				},
+				&cli.BoolFlag{
+					Name:  "disable-keep-alive",
+					Usage: "use this flag to disable http keep-alive",
+				},
+				&cli.StringFlag{
+					Name:  "ca",
+					Usage: "ca certificate to verify peer against",
+				},
+				&cli.StringFlag{
+					Name:  "cert",
+					Usage: "client authentication certificate",
+				},
+				&cli.StringFlag{
+					Name:  "key",
+					Usage: "client authentication key",
+				},
+				&cli.StringFlag{
+					Name:  "plugin-dir",
+					Usage: "load every plugin binary found in `DIR` and keep it alive for the duration of the run",
+				},
+				&cli.BoolFlag{
+					Name:  "strict-plugins",
+					Usage: "abort the run if a plugin's OnStart or OnMetrics hook returns an error, and fail the request if its BeforeRequest or AfterResponse hook does, instead of just logging it",
+				},
+				&cli.IntFlag{
+					Name:  "retry-max",
+					Usage: "max number of retries for a request matched by --retry-on (retries don't count toward -n)",
+				},
+				&cli.StringFlag{
+					Name:  "retry-on",
+					Usage: "comma-separated outcomes to retry: 5xx,connreset,timeout",
+				},
+				&cli.DurationFlag{
+					Name:  "retry-backoff-base",
+					Usage: "base delay for exponential backoff between retries (example: 100ms)",
+					Value: 100 * time.Millisecond,
+				},
+				&cli.DurationFlag{
+					Name:  "retry-backoff-max",
+					Usage: "cap on the exponential backoff delay between retries (example: 5s)",
+					Value: 5 * time.Second,
+				},
+				&cli.BoolFlag{
+					Name:  "retry-jitter",
+					Usage: "randomize the backoff delay (full jitter) instead of sleeping the full computed delay",
+				},
+				&cli.StringFlag{
+					Name:  "otlp-endpoint",
+					Usage: "send metrics and per-request traces to an OpenTelemetry Collector at `HOST:PORT` over OTLP",
+				},
+				&cli.StringFlag{
+					Name:  "otlp-headers",
+					Usage: "comma-separated key=value headers to send with every OTLP request",
+				},
+				&cli.BoolFlag{
+					Name:  "otlp-insecure",
+					Usage: "disable transport security for the OTLP connection",
+				},
+			},
+			Action: validateCLI,
+		},
+		{
+			Name:  "ws",
+			Usage: "start a WebSocket load-test",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "u",
+					Aliases:  []string{"url"},
+					Usage:    "the websocket url (ws{s}://{host}) to be used",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:    "c",
+					Aliases: []string{"concurrency"},
+					Usage:   "number of concurrent connections",
+					Value:   1,
+				},
+				&cli.StringFlag{
+					Name:  "message-file",
+					Usage: "file containing the message payload to send over the connection",
+				},
+				&cli.IntFlag{
+					Name:  "message-count",
+					Usage: "number of messages to send per connection",
+					Value: 1,
+				},
+				&cli.DurationFlag{
+					Name:  "send-interval",
+					Usage: "time to wait between messages on a connection (example: 100ms)",
+				},
+				&cli.StringFlag{
+					Name:    "d",
+					Aliases: []string{"duration"},
+					Usage:   "run each connection for the duration in seconds instead of a fixed message-count (example: do messages for 30s)",
+				},
+				&cli.IntFlag{
+					Name:    "t",
+					Aliases: []string{"timeout"},
+					Usage:   "http client timeout",
+					Value:   5,
+				},
+				&cli.StringFlag{
+					Name:    "p",
+					Aliases: []string{"prompushgwurl"},
+					Usage:   "specify prometheus push gateway url to send metrics (optional)",
+				},
+				&cli.BoolFlag{
+					Name:    "C",
+					Aliases: []string{"cloudwatch"},
+					Usage:   "enable to send metrics to AWS Cloudwatch",
+				},
+				&cli.StringFlag{
+					Name:    "H",
+					Aliases: []string{"header"},
+					Usage:   "add arbitrary header, eg. 'Host: www.example.com'",
+				},
+				&cli.BoolFlag{
+					Name:    "F",
+					Aliases: []string{"json-metrics"},
+					Usage:   "outputs metrics to a json file by setting flag to true",
+				},
+				&cli.StringFlag{
+					Name:  "json-metrics-file",
+					Usage: "outputs metrics to a custom json filepath, if json-metrics is set to true",
+				},
+				&cli.StringFlag{
+					Name:  "ca",
+					Usage: "ca certificate to verify peer against",
+				},
+				&cli.StringFlag{
+					Name:  "cert",
+					Usage: "client authentication certificate",
+				},
+				&cli.StringFlag{
+					Name:  "key",
+					Usage: "client authentication key",
+				},
+				&cli.StringFlag{
+					Name:  "plugin-dir",
+					Usage: "load every plugin binary found in `DIR` and keep it alive for the duration of the run",
+				},
+				&cli.BoolFlag{
+					Name:  "strict-plugins",
+					Usage: "abort the run if a plugin's OnStart or OnMetrics hook returns an error, and fail the request if its BeforeRequest or AfterResponse hook does, instead of just logging it",
+				},
+			},
+			Action: validateCLIWebSocket,
+		},
+		{
+			Name:  "h2",
+			Usage: "start an HTTP/2 load-test with stream multiplexing",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "u",
+					Aliases:  []string{"url"},
+					Usage:    "the url (absoluteURI) to be used",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:    "c",
+					Aliases: []string{"concurrency"},
+					Usage:   "number of concurrent connections",
+					Value:   1,
+				},
+				&cli.IntFlag{
+					Name:    "n",
+					Aliases: []string{"requests"},
+					Usage:   "number of requests to perform, spread as streams across the concurrent connections",
+					Value:   1,
+				},
+				&cli.StringFlag{
+					Name:    "d",
+					Aliases: []string{"duration"},
+					Usage:   "run each connection for the duration in seconds instead of a fixed request count (example: do requests for 30s)",
+				},
+				&cli.IntFlag{
+					Name:    "t",
+					Aliases: []string{"timeout"},
+					Usage:   "http client timeout",
+					Value:   5,
+				},
+				&cli.StringFlag{
+					Name:    "p",
+					Aliases: []string{"prompushgwurl"},
+					Usage:   "specify prometheus push gateway url to send metrics (optional)",
+				},
+				&cli.BoolFlag{
+					Name:    "C",
+					Aliases: []string{"cloudwatch"},
+					Usage:   "enable to send metrics to AWS Cloudwatch",
+				},
+				&cli.StringFlag{
+					Name:    "H",
+					Aliases: []string{"header"},
+					Usage:   "add arbitrary header, eg. 'Host: www.example.com'",
+				},
+				&cli.BoolFlag{
+					Name:    "F",
+					Aliases: []string{"json-metrics"},
+					Usage:   "outputs metrics to a json file by setting flag to true",
+				},
+				&cli.StringFlag{
+					Name:  "json-metrics-file",
+					Usage: "outputs metrics to a custom json filepath, if json-metrics is set to true",
+				},
+				&cli.StringFlag{
+					Name:  "ca",
+					Usage: "ca certificate to verify peer against",
+				},
+				&cli.StringFlag{
+					Name:  "cert",
+					Usage: "client authentication certificate",
+				},
+				&cli.StringFlag{
+					Name:  "key",
+					Usage: "client authentication key",
+				},
+				&cli.StringFlag{
+					Name:  "plugin-dir",
+					Usage: "load every plugin binary found in `DIR` and keep it alive for the duration of the run",
+				},
+				&cli.BoolFlag{
+					Name:  "strict-plugins",
+					Usage: "abort the run if a plugin's OnStart or OnMetrics hook returns an error, and fail the request if its BeforeRequest or AfterResponse hook does, instead of just logging it",
+				},
+			},
+			Action: validateCLIHTTP2,
+		},
+		{
+			Name:  "scenario",
+			Usage: "run a multi-step scenario load-test from a YAML file",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "f",
+					Aliases:  []string{"file"},
+					Usage:    "scenario `FILE` describing the steps to run",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:    "c",
+					Aliases: []string{"concurrency"},
+					Usage:   "number of concurrent virtual users",
+					Value:   1,
+				},
+				&cli.IntFlag{
+					Name:    "n",
+					Aliases: []string{"requests"},
+					Usage:   "number of scenario iterations to perform, spread across the virtual users",
+					Value:   1,
+				},
+				&cli.StringFlag{
+					Name:    "d",
+					Aliases: []string{"duration"},
+					Usage:   "set the duration in seconds of the scenario run instead of a fixed iteration count",
+				},
+				&cli.IntFlag{
+					Name:    "t",
+					Aliases: []string{"timeout"},
+					Usage:   "http client timeout",
+					Value:   5,
+				},
+				&cli.StringFlag{
+					Name:    "p",
+					Aliases: []string{"prompushgwurl"},
+					Usage:   "specify prometheus push gateway url to send metrics (optional)",
+				},
+				&cli.BoolFlag{
+					Name:    "C",
+					Aliases: []string{"cloudwatch"},
+					Usage:   "enable to send metrics to AWS Cloudwatch",
+				},
+				&cli.BoolFlag{
+					Name:    "F",
+					Aliases: []string{"json-metrics"},
+					Usage:   "outputs metrics to a json file by setting flag to true",
+				},
+				&cli.StringFlag{
+					Name:  "json-metrics-file",
+					Usage: "outputs metrics to a custom json filepath, if json-metrics is set to true",
+				},
+				&cli.StringFlag{
+					Name:  "ca",
+					Usage: "ca certificate to verify peer against",
+				},
+				&cli.StringFlag{
+					Name:  "cert",
+					Usage: "client authentication certificate",
+				},
+				&cli.StringFlag{
+					Name:  "key",
+					Usage: "client authentication key",
+				},
+				&cli.StringFlag{
+					Name:  "plugin-dir",
+					Usage: "load every plugin binary found in `DIR` and keep it alive for the duration of the run",
+				},
+				&cli.BoolFlag{
+					Name:  "strict-plugins",
+					Usage: "abort the run if a plugin's OnStart or OnMetrics hook returns an error, and fail the request if its BeforeRequest or AfterResponse hook does, instead of just logging it",
+				},
+			},
+			Action: validateCLIScenario,
+		},
+		{
+			Name:  "coordinator",
+			Usage: "shard a load-test across registered workers and merge their results",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "u",
+					Aliases:  []string{"url"},
+					Usage:    "the url (absoluteURI) to be used",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:    "c",
+					Aliases: []string{"concurrency"},
+					Usage:   "total number of concurrent users, split across every registered worker",
+					Value:   1,
+				},
+				&cli.IntFlag{
+					Name:    "n",
+					Aliases: []string{"requests"},
+					Usage:   "total number of requests to perform, split across every registered worker",
+					Value:   1,
+				},
+				&cli.StringFlag{
+					Name:    "d",
+					Aliases: []string{"duration"},
+					Usage:   "set the duration in seconds of the load test instead of a fixed request count",
+				},
+				&cli.IntFlag{
+					Name:    "t",
+					Aliases: []string{"timeout"},
+					Usage:   "http client timeout",
+					Value:   5,
+				},
+				&cli.StringFlag{
+					Name:  "listen",
+					Usage: "`ADDR` the coordinator listens on for worker registrations",
+					Value: ":7701",
+				},
+				&cli.StringFlag{
+					Name:  "token",
+					Usage: "shared secret workers must present to register",
+				},
+				&cli.IntFlag{
+					Name:  "wait",
+					Usage: "seconds to wait for workers to register before starting the run",
+					Value: 10,
+				},
+				&cli.StringFlag{
+					Name:    "p",
+					Aliases: []string{"prompushgwurl"},
+					Usage:   "specify prometheus push gateway url to send metrics (optional)",
+				},
+				&cli.BoolFlag{
+					Name:    "C",
+					Aliases: []string{"cloudwatch"},
+					Usage:   "enable to send metrics to AWS Cloudwatch",
+				},
+				&cli.StringFlag{
+					Name:    "H",
+					Aliases: []string{"header"},
+					Usage:   "add arbitrary header, eg. 'Host: www.example.com'",
+				},
+				&cli.BoolFlag{
+					Name:    "F",
+					Aliases: []string{"json-metrics"},
+					Usage:   "outputs metrics to a json file by setting flag to true",
+				},
+				&cli.StringFlag{
+					Name:  "json-metrics-file",
+					Usage: "outputs metrics to a custom json filepath, if json-metrics is set to true",
+				},
+			},
+			Action: validateCLICoordinator,
+		},
+		{
+			Name:  "worker",
+			Usage: "register with a coordinator and run the load-test shards it assigns",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "coordinator",
+					Usage:    "`ADDR` of the coordinator to register with",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "listen",
+					Usage: "`ADDR` this worker listens on for the coordinator's StartJob calls",
+					Value: ":7702",
+				},
+				&cli.StringFlag{
+					Name:  "token",
+					Usage: "shared secret to present when registering",
+				},
+			},
+			Action: validateCLIWorker,
+		},
+	}
+
+	err := app.Run(args)
+	if err != nil {
+		log.Fatalf("error running application: %s\n", err)
 	}
-	return nil
 }
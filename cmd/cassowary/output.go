@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+const (
+	summaryTable = `` + "\n\n" +
+		` TCP Connect.....................: Avg/mean=%sms ` + "\t" + `Median=%sms` + "\t" + `p(95)=%sms` + "\n" +
+		` Server Processing...............: Avg/mean=%sms ` + "\t" + `Median=%sms` + "\t" + `p(95)=%sms` + "\n" +
+		` Content Transfer................: Avg/mean=%sms ` + "\t" + `Median=%sms` + "\t" + `p(95)=%sms` + "\n" +
+		`` + "\n" +
+		`Summary: ` + "\n" +
+		` Total Req.......................: %s` + "\n" +
+		` Failed Req......................: %s` + "\n" +
+		` DNS Lookup......................: %sms` + "\n" +
+		` Req/s...........................: %s` + "\n\n"
+
+	wsSummaryTable = `` + "\n\n" +
+		` Handshake........................: Avg/mean=%sms` + "\n" +
+		` Time to First Message............: Avg/mean=%sms` + "\n" +
+		` Message RTT......................: Avg/mean=%sms ` + "\t" + `Median=%sms` + "\t" + `p(95)=%sms` + "\n" +
+		`` + "\n" +
+		`Summary: ` + "\n" +
+		` Total Messages...................: %s` + "\n\n"
+
+	h2SummaryTable = `` + "\n\n" +
+		` Connection Setup.................: Avg/mean=%sms` + "\n" +
+		` Stream Latency....................: Avg/mean=%sms ` + "\t" + `Median=%sms` + "\t" + `p(95)=%sms` + "\n" +
+		` Streams per Connection............: %s` + "\n" +
+		`` + "\n" +
+		`Summary: ` + "\n" +
+		` Total Req.........................: %s` + "\n" +
+		` Req/s.............................: %s` + "\n\n"
+
+	scenarioStepLine = ` %-30s............: Avg/mean=%sms ` + "\t" + `Median=%sms` + "\t" + `p(95)=%sms` + "\t" + `Failed=%s` + "\n"
+
+	scenarioSummaryTable = `` + "\n\n" +
+		`Summary: ` + "\n" +
+		` Total Req.......................: %s` + "\n" +
+		` Failed Req......................: %s` + "\n" +
+		` Req/s...........................: %s` + "\n\n"
+)
+
+func printf(format string, a ...interface{}) {
+	fmt.Fprintf(color.Output, format, a...)
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+func (c *Cassowary) cloudwatchDatum(name string, unit string, value float64) *cloudwatch.MetricDatum {
+	return &cloudwatch.MetricDatum{
+		MetricName: aws.String(name),
+		Unit:       aws.String(unit),
+		Value:      aws.Float64(value),
+		Dimensions: []*cloudwatch.Dimension{
+			&cloudwatch.Dimension{
+				Name:  aws.String("Site"),
+				Value: aws.String(c.BaseURL),
+			},
+		},
+	}
+}
+
+// PutCloudwatchMetrics exports metrics to AWS Cloudwatch
+func (c *Cassowary) PutCloudwatchMetrics(svc cloudwatchiface.CloudWatchAPI, metrics ResultMetrics) (*cloudwatch.PutMetricDataOutput, error) {
+	metricData := []*cloudwatch.MetricDatum{
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("tcp_connect_mean"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.TCPStats.TCPMean),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("tcp_connect_median"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.TCPStats.TCPMedian),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("tcp_connect_95p"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.TCPStats.TCP95p),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("server_processing_mean"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.ProcessingStats.ServerProcessingMean),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("server_processing_median"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.ProcessingStats.ServerProcessingMedian),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("server_processing_95p"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.ProcessingStats.ServerProcessing95p),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("content_transfer_mean"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.ContentStats.ContentTransferMean),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("content_transfer_median"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.ContentStats.ContentTransferMedian),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("content_transfer_95p"),
+			Unit:       aws.String("Milliseconds"),
+			Value:      aws.Float64(metrics.ContentStats.ContentTransfer95p),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("total_requests"),
+			Unit:       aws.String("Count"),
+			Value:      aws.Float64(float64(metrics.TotalRequests)),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		&cloudwatch.MetricDatum{
+			MetricName: aws.String("failed_requests"),
+			Unit:       aws.String("Count"),
+			Value:      aws.Float64(float64(metrics.FailedRequests)),
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  aws.String("Site"),
+					Value: aws.String(c.BaseURL),
+				},
+			},
+		},
+		c.cloudwatchDatum("requests_per_second", "Count/Second", metrics.RequestsPerSecond),
+	}
+
+	if metrics.WebSocketStats != nil {
+		metricData = append(metricData,
+			c.cloudwatchDatum("websocket_handshake_mean", "Milliseconds", metrics.WebSocketStats.HandshakeMean),
+			c.cloudwatchDatum("websocket_time_to_first_message", "Milliseconds", metrics.WebSocketStats.TimeToFirstMessage),
+			c.cloudwatchDatum("websocket_message_rtt_mean", "Milliseconds", metrics.WebSocketStats.MessageRTTMean),
+			c.cloudwatchDatum("websocket_message_rtt_95p", "Milliseconds", metrics.WebSocketStats.MessageRTT95p),
+		)
+	}
+
+	if metrics.HTTP2Stats != nil {
+		metricData = append(metricData,
+			c.cloudwatchDatum("http2_connection_setup_mean", "Milliseconds", metrics.HTTP2Stats.ConnectionSetupMean),
+			c.cloudwatchDatum("http2_stream_latency_mean", "Milliseconds", metrics.HTTP2Stats.StreamLatencyMean),
+			c.cloudwatchDatum("http2_stream_latency_95p", "Milliseconds", metrics.HTTP2Stats.StreamLatency95p),
+		)
+	}
+
+	resp, err := svc.PutMetricData(&cloudwatch.PutMetricDataInput{
+		Namespace:  aws.String("Cassowary/Metrics"),
+		MetricData: metricData,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// HTTP2Stats captures HTTP/2-specific timings: connection setup is
+// measured once per concurrent connection, while stream latency is
+// measured per request multiplexed over it.
+type HTTP2Stats struct {
+	ConnectionSetupMean float64 `json:"connection_setup_mean"`
+	StreamLatencyMean   float64 `json:"stream_latency_mean"`
+	StreamLatencyMedian float64 `json:"stream_latency_median"`
+	StreamLatency95p    float64 `json:"stream_latency_95th_percentile"`
+	StreamsPerConn      float64 `json:"streams_per_connection"`
+}
+
+type http2ConnMetrics struct {
+	Setup   float64
+	Streams []float64
+	Failed  int
+}
+
+// runHTTP2Conn drives one HTTP/2 connection's worth of the load test. A
+// failed warmup fails every stream that would have run on this
+// connection; a failed individual stream only fails that stream. Either
+// way the failure is recorded in outPutChan rather than aborting the
+// whole run, the same way a transport error in doRequest or a scenario
+// step no longer takes down every other concurrent connection.
+func (c *Cassowary) runHTTP2Conn(httpClient *http.Client, streams int, deadline time.Time, outPutChan chan<- http2ConnMetrics) {
+	setupStart := time.Now()
+	warmup, err := http.NewRequest("GET", c.BaseURL, nil)
+	if err != nil {
+		log.Printf("%v", err)
+		outPutChan <- http2ConnMetrics{Failed: streams}
+		return
+	}
+	if len(c.RequestHeader) == 2 {
+		warmup.Header.Add(c.RequestHeader[0], c.RequestHeader[1])
+	}
+	resp, err := httpClient.Do(warmup)
+	if err != nil {
+		log.Printf("unable to establish http2 connection: %v", err)
+		outPutChan <- http2ConnMetrics{Failed: streams}
+		return
+	}
+	resp.Body.Close()
+
+	metrics := http2ConnMetrics{
+		Setup: float64(time.Since(setupStart) / time.Millisecond),
+	}
+
+	runBatch := func(n int) {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		wg.Add(n)
+
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+
+				fail := func(format string, args ...interface{}) {
+					log.Printf(format, args...)
+					mu.Lock()
+					metrics.Failed++
+					mu.Unlock()
+				}
+
+				req, err := http.NewRequest(c.HTTPMethod, c.BaseURL, nil)
+				if err != nil {
+					fail("%v", err)
+					return
+				}
+				if len(c.RequestHeader) == 2 {
+					req.Header.Add(c.RequestHeader[0], c.RequestHeader[1])
+				}
+				if err := c.Plugins.BeforeRequest(context.Background(), req); err != nil {
+					fail("%v", err)
+					return
+				}
+
+				start := time.Now()
+				resp, err := httpClient.Do(req)
+				if err != nil {
+					fail("unable to perform http2 request: %v", err)
+					return
+				}
+				resp.Body.Close()
+				latency := float64(time.Since(start) / time.Millisecond)
+
+				mu.Lock()
+				metrics.Streams = append(metrics.Streams, latency)
+				mu.Unlock()
+
+				if err := c.Plugins.AfterResponse(context.Background(), RequestResult{
+					Method:     req.Method,
+					URL:        req.URL.String(),
+					StatusCode: resp.StatusCode,
+					Latency:    latency,
+				}); err != nil {
+					fail("%v", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	if deadline.IsZero() {
+		runBatch(streams)
+	} else {
+		for time.Now().Before(deadline) {
+			runBatch(streams)
+		}
+	}
+
+	outPutChan <- metrics
+}
+
+// CoordinateHTTP2 drives an HTTP/2 load test over ConcurrencyLevel
+// connections, reusing RequestHeader and TLSConfig the same way the
+// regular HTTP mode does, multiplexing Requests/ConcurrencyLevel streams
+// across each connection (or, when Duration is set, repeating batches of
+// streams until Duration elapses instead) so per-stream latency can be
+// measured separately from the one-time connection setup cost.
+func (c *Cassowary) CoordinateHTTP2() (ResultMetrics, error) {
+	httpClient := &http.Client{
+		Timeout:   time.Second * time.Duration(c.Timeout),
+		Transport: &http2.Transport{TLSClientConfig: c.TLSConfig},
+	}
+
+	if err := c.Plugins.OnStart(context.Background(), c.BaseURL); err != nil {
+		return ResultMetrics{}, err
+	}
+	defer c.Plugins.Stop(context.Background())
+
+	// Distribute Requests across ConcurrencyLevel connections the same way
+	// shardJob does for the cluster coordinator: base streams per
+	// connection, remainder handed to the first connections, rather than
+	// truncating and silently dropping requests.
+	baseStreams, streamRemainder := c.Requests/c.ConcurrencyLevel, c.Requests%c.ConcurrencyLevel
+
+	// With Duration set, each connection keeps firing batches of streams
+	// until the deadline instead of a fixed stream count, the same
+	// request/duration tradeoff the WebSocket and regular HTTP modes make.
+	var deadline time.Time
+	if c.Duration > 0 {
+		deadline = time.Now().Add(time.Duration(c.Duration) * time.Second)
+	}
+
+	var wg sync.WaitGroup
+	channel := make(chan http2ConnMetrics, c.ConcurrencyLevel)
+	wg.Add(c.ConcurrencyLevel)
+
+	start := time.Now()
+	for i := 0; i < c.ConcurrencyLevel; i++ {
+		streams := baseStreams
+		if i < streamRemainder {
+			streams++
+		}
+		if streams == 0 {
+			streams = 1
+		}
+		go func(streams int) {
+			defer wg.Done()
+			c.runHTTP2Conn(httpClient, streams, deadline, channel)
+		}(streams)
+	}
+	wg.Wait()
+	close(channel)
+	end := time.Since(start)
+
+	var setups, streamLatencies []float64
+	failedStreams := 0
+	for item := range channel {
+		failedStreams += item.Failed
+		setups = append(setups, item.Setup)
+		streamLatencies = append(streamLatencies, item.Streams...)
+	}
+
+	totalStreams := len(streamLatencies) + failedStreams
+	outPut := ResultMetrics{
+		BaseURL:           c.BaseURL,
+		TotalRequests:     totalStreams,
+		FailedRequests:    failedStreams,
+		RequestsPerSecond: requestsPerSecond(totalStreams, end),
+		HTTP2Stats: &HTTP2Stats{
+			ConnectionSetupMean: calcMean(setups),
+			StreamLatencyMean:   calcMean(streamLatencies),
+			StreamLatencyMedian: calcMedian(streamLatencies),
+			StreamLatency95p:    stringToFloat(calc95Percentile(streamLatencies)),
+			StreamsPerConn:      float64(c.Requests) / float64(c.ConcurrencyLevel),
+		},
+	}
+
+	if err := c.Plugins.OnMetrics(context.Background(), outPut); err != nil {
+		return outPut, err
+	}
+
+	return outPut, nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoordinateHTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	cass := &Cassowary{
+		BaseURL:          srv.URL,
+		ConcurrencyLevel: 3,
+		Requests:         7,
+		Timeout:          5,
+		TLSConfig:        &tls.Config{InsecureSkipVerify: true},
+	}
+
+	metrics, err := cass.CoordinateHTTP2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Requests=7 spread across 3 connections should run all 7, not
+	// truncate to 6 (7/3=2 per connection, dropping the remainder).
+	if metrics.TotalRequests != 7 {
+		t.Errorf("got %d total requests, want 7", metrics.TotalRequests)
+	}
+	if metrics.FailedRequests != 0 {
+		t.Errorf("got %d failed requests, want 0", metrics.FailedRequests)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestRunHTTP2ConnOneStreamFails ensures a single failed stream among
+// many concurrent ones on the same connection is recorded as a failure
+// rather than aborting the whole connection's batch.
+func TestRunHTTP2ConnOneStreamFails(t *testing.T) {
+	var seen int32
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&seen, 1) == 2 {
+				return nil, errors.New("simulated stream failure")
+			}
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(nil)}, nil
+		}),
+	}
+
+	cass := &Cassowary{BaseURL: "https://example.com", HTTPMethod: "GET"}
+	channel := make(chan http2ConnMetrics, 1)
+
+	cass.runHTTP2Conn(httpClient, 4, time.Time{}, channel)
+
+	metrics := <-channel
+	if metrics.Failed != 1 {
+		t.Errorf("got %d failed streams, want 1", metrics.Failed)
+	}
+	if len(metrics.Streams) != 3 {
+		t.Errorf("got %d successful streams, want 3", len(metrics.Streams))
+	}
+}
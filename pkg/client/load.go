@@ -0,0 +1,415 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/schollz/progressbar"
+)
+
+type durationMetrics struct {
+	DNSLookup        float64
+	TCPConn          float64
+	TLSHandshake     float64
+	ServerProcessing float64
+	ContentTransfer  float64
+	StatusCode       int
+	Latency          float64
+	Attempts         int
+	RetryExhausted   bool
+}
+
+// doRequest sends a single attempt at URLitem and returns its timings and
+// status code alongside any transport-level error. It does not retry;
+// runLoadTest wraps it with the Cassowary's RetryPolicy. attempt is the
+// 1-based attempt number, reported to plugins and Events as-is.
+func (c *Cassowary) doRequest(URLitem string, attempt int) (durationMetrics, error) {
+	start := time.Now()
+	var request *http.Request
+	var err error
+
+	if c.FileMode {
+		request, err = http.NewRequest("GET", c.BaseURL+URLitem, nil)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	} else {
+		switch c.HTTPMethod {
+		case "POST":
+			request, err = http.NewRequest("POST", c.BaseURL, bytes.NewBuffer(c.Data))
+			request.Header.Set("Content-Type", "application/json")
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+		case "PUT":
+			request, err = http.NewRequest("PUT", c.BaseURL, bytes.NewBuffer(c.Data))
+			request.Header.Set("Content-Type", "application/json")
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+		default:
+			request, err = http.NewRequest("GET", c.BaseURL, nil)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+	}
+
+	if len(c.RequestHeader) == 2 {
+		request.Header.Add(c.RequestHeader[0], c.RequestHeader[1])
+	}
+
+	if err := c.Plugins.BeforeRequest(context.Background(), request); err != nil {
+		return durationMetrics{}, err
+	}
+
+	var t0, t1, t2, t3, t4, t5, t6 time.Time
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(_ httptrace.DNSStartInfo) { t0 = time.Now() },
+		DNSDone:  func(_ httptrace.DNSDoneInfo) { t1 = time.Now() },
+		ConnectStart: func(_, _ string) {
+			if t1.IsZero() {
+				// connecting directly to IP
+				t1 = time.Now()
+			}
+		},
+		ConnectDone: func(net, addr string, err error) {
+			if err != nil {
+				// c.Client.Do below returns this error to runLoadTest.
+				log.Printf("unable to connect to host %v: %v", addr, err)
+				return
+			}
+			t2 = time.Now()
+
+		},
+		GotConn:              func(_ httptrace.GotConnInfo) { t3 = time.Now() },
+		GotFirstResponseByte: func() { t4 = time.Now() },
+		TLSHandshakeStart:    func() { t5 = time.Now() },
+		TLSHandshakeDone:     func(_ tls.ConnectionState, _ error) { t6 = time.Now() },
+	}
+
+	request = request.WithContext(httptrace.WithClientTrace(context.Background(), trace))
+	resp, err := c.Client.Do(request)
+	if err != nil {
+		return durationMetrics{}, err
+	}
+	if resp != nil {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		if err != nil {
+			fmt.Println("Failed to read HTTP response body", err)
+		}
+		resp.Body.Close()
+	}
+
+	result := RequestResult{
+		Method:     request.Method,
+		URL:        request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Latency:    float64(time.Since(start) / time.Millisecond),
+		Attempt:    attempt,
+	}
+
+	if err := c.Plugins.AfterResponse(context.Background(), result); err != nil {
+		return durationMetrics{}, err
+	}
+
+	if c.Events != nil {
+		c.Events <- result
+	}
+
+	// Body fully read here
+	t7 := time.Now()
+	if t0.IsZero() {
+		// we skipped DNS
+		t0 = t1
+	}
+
+	out := durationMetrics{
+		DNSLookup: float64(t1.Sub(t0) / time.Millisecond), // dns lookup
+		//TCPConn:          float64(t3.Sub(t1) / time.Millisecond), // tcp connection
+		ServerProcessing: float64(t4.Sub(t3) / time.Millisecond), // server processing
+		ContentTransfer:  float64(t7.Sub(t4) / time.Millisecond), // content transfer
+		StatusCode:       resp.StatusCode,
+		Latency:          result.Latency,
+	}
+
+	if c.IsTLS {
+		out.TCPConn = float64(t2.Sub(t1) / time.Millisecond)
+		out.TLSHandshake = float64(t6.Sub(t5) / time.Millisecond) // tls handshake
+	} else {
+		out.TCPConn = float64(t3.Sub(t1) / time.Millisecond)
+	}
+
+	return out, nil
+}
+
+func (c *Cassowary) runLoadTest(outPutChan chan<- durationMetrics, workerChan chan string) {
+	for URLitem := range workerChan {
+		attempts := 0
+		var out durationMetrics
+		var err error
+
+		for {
+			attempts++
+			out, err = c.doRequest(URLitem, attempts)
+
+			if !matchesRetryOn(c.RetryPolicy.RetryOn, out.StatusCode, err) {
+				if err != nil {
+					// No HTTP response to report a status code from (transport
+					// error, or a strict plugin hook rejecting the request) and
+					// the failure isn't eligible for retry: count it as a
+					// failed request with a synthetic status code, the same
+					// way a retry-exhausted request is counted, rather than
+					// aborting the whole run and every other concurrent
+					// worker's in-flight results.
+					out.StatusCode = 599
+				}
+				break
+			}
+
+			if attempts > c.RetryPolicy.MaxRetries {
+				out.RetryExhausted = true
+				if err != nil {
+					// no HTTP response to report a status code from; use a
+					// synthetic one so this still counts as a failed request.
+					out.StatusCode = 599
+				}
+				break
+			}
+
+			time.Sleep(backoffDuration(c.RetryPolicy, attempts-1))
+		}
+		out.Attempts = attempts
+
+		if c.DisableTerminalOutput != true {
+			c.Bar.Add(1)
+		}
+
+		if c.RawOutput != nil {
+			c.RawOutput <- RequestTiming{
+				DNSLookup:        out.DNSLookup,
+				TCPConn:          out.TCPConn,
+				TLSHandshake:     out.TLSHandshake,
+				ServerProcessing: out.ServerProcessing,
+				ContentTransfer:  out.ContentTransfer,
+				StatusCode:       out.StatusCode,
+			}
+		}
+
+		outPutChan <- out
+	}
+}
+
+// Coordinate bootstraps the load test based on values in Cassowary struct
+func (c *Cassowary) Coordinate() (ResultMetrics, error) {
+	var dnsDur []float64
+	var tcpDur []float64
+	var tlsDur []float64
+	var serverDur []float64
+	var transferDur []float64
+	var statusCodes []int
+	var attemptCounts []float64
+	var retryLatencies []float64
+	retriesExhausted := 0
+
+	tls, err := isTLS(c.BaseURL)
+	if err != nil {
+		return ResultMetrics{}, err
+	}
+	c.IsTLS = tls
+
+	if err := c.Plugins.OnStart(context.Background(), c.BaseURL); err != nil {
+		return ResultMetrics{}, err
+	}
+	defer c.Plugins.Stop(context.Background())
+
+	c.Client = &http.Client{
+		Timeout: time.Second * time.Duration(c.Timeout),
+		Transport: &http.Transport{
+			TLSClientConfig:     c.TLSConfig,
+			MaxIdleConnsPerHost: 10000,
+			DisableCompression:  false,
+			DisableKeepAlives:   c.DisableKeepAlive,
+		},
+	}
+
+	if c.FileMode {
+		if c.Requests > len(c.URLPaths) {
+			c.URLPaths = generateSuffixes(c.URLPaths, c.Requests)
+		}
+		c.Requests = len(c.URLPaths)
+	}
+
+	c.Bar = progressbar.New(c.Requests)
+
+	if c.DisableTerminalOutput != true {
+		col := color.New(color.FgCyan).Add(color.Underline)
+		col.Printf("\nStarting Load Test with %d requests using %d concurrent users\n\n", c.Requests, c.ConcurrencyLevel)
+	}
+
+	var wg sync.WaitGroup
+	channel := make(chan durationMetrics, c.Requests)
+	workerChan := make(chan string)
+
+	wg.Add(c.ConcurrencyLevel)
+	start := time.Now()
+
+	for i := 0; i < c.ConcurrencyLevel; i++ {
+		go func() {
+			c.runLoadTest(channel, workerChan)
+			wg.Done()
+		}()
+	}
+
+	if c.Duration > 0 {
+		durationMS := c.Duration * 1000
+		nextTick := durationMS / c.Requests
+		ticker := time.NewTicker(time.Duration(nextTick) * time.Millisecond)
+		done := make(chan bool)
+		iter := 0
+
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case _ = <-ticker.C:
+					if c.FileMode {
+						workerChan <- c.URLPaths[iter]
+						iter++
+					} else {
+						workerChan <- "a"
+					}
+				}
+			}
+		}()
+
+		time.Sleep(time.Duration(durationMS) * time.Millisecond)
+		ticker.Stop()
+		done <- true
+	}
+
+	if c.Duration == 0 && c.FileMode {
+		for _, line := range c.URLPaths {
+			workerChan <- line
+		}
+	} else if c.Duration == 0 && !c.FileMode {
+		for i := 0; i < c.Requests; i++ {
+			workerChan <- "a"
+		}
+	}
+
+	close(workerChan)
+	wg.Wait()
+	close(channel)
+
+	end := time.Since(start)
+	if c.DisableTerminalOutput != true {
+		fmt.Println(end)
+	}
+
+	for item := range channel {
+		if item.DNSLookup != 0 {
+			dnsDur = append(dnsDur, item.DNSLookup)
+		}
+		if item.TCPConn < 1000 {
+			tcpDur = append(tcpDur, item.TCPConn)
+		}
+		if c.IsTLS {
+			tlsDur = append(tlsDur, item.TLSHandshake)
+		}
+		serverDur = append(serverDur, item.ServerProcessing)
+		transferDur = append(transferDur, item.ContentTransfer)
+		statusCodes = append(statusCodes, item.StatusCode)
+
+		if c.RetryPolicy.MaxRetries > 0 {
+			retryLatencies = append(retryLatencies, item.Latency)
+			if item.RetryExhausted {
+				retriesExhausted++
+			} else if failedRequests([]int{item.StatusCode}) == 0 {
+				// AttemptsMean/Median/95p is documented as
+				// attempts-to-success: a request that exhausted every
+				// retry, or failed in a way RetryOn doesn't match, has no
+				// "success" to count attempts to, so it's excluded here
+				// rather than skewing the stat with failures.
+				attemptCounts = append(attemptCounts, float64(item.Attempts))
+			}
+		}
+	}
+
+	// DNS
+	dnsMedian := calcMedian(dnsDur)
+
+	// TCP
+	tcpMean := calcMean(tcpDur)
+	tcpMedian := calcMedian(tcpDur)
+	tcp95 := calc95Percentile(tcpDur)
+
+	// Server Processing
+	serverMean := calcMean(serverDur)
+	serverMedian := calcMedian(serverDur)
+	server95 := calc95Percentile(serverDur)
+
+	// Content Transfer
+	transferMean := calcMean(transferDur)
+	transferMedian := calcMedian(transferDur)
+	transfer95 := calc95Percentile(transferDur)
+
+	// Request per second
+	reqS := requestsPerSecond(c.Requests, end)
+
+	// Failed Requests
+	failedR := failedRequests(statusCodes)
+
+	outPut := ResultMetrics{
+		BaseURL:           c.BaseURL,
+		FailedRequests:    failedR,
+		RequestsPerSecond: reqS,
+		TotalRequests:     c.Requests,
+		DNSMedian:         dnsMedian,
+		TCPStats: tcpStats{
+			TCPMean:   tcpMean,
+			TCPMedian: tcpMedian,
+			TCP95p:    stringToFloat(tcp95),
+		},
+		ProcessingStats: serverProcessingStats{
+			ServerProcessingMean:   serverMean,
+			ServerProcessingMedian: serverMedian,
+			ServerProcessing95p:    stringToFloat(server95),
+		},
+		ContentStats: contentTransfer{
+			ContentTransferMean:   transferMean,
+			ContentTransferMedian: transferMedian,
+			ContentTransfer95p:    stringToFloat(transfer95),
+		},
+	}
+
+	if c.RetryPolicy.MaxRetries > 0 {
+		outPut.RetryStats = &RetryStats{
+			AttemptsMean:   calcMean(attemptCounts),
+			AttemptsMedian: calcMedian(attemptCounts),
+			Attempts95p:    stringToFloat(calc95Percentile(attemptCounts)),
+			LatencyMean:    calcMean(retryLatencies),
+			LatencyMedian:  calcMedian(retryLatencies),
+			Latency95p:     stringToFloat(calc95Percentile(retryLatencies)),
+			Exhausted:      retriesExhausted,
+		}
+	}
+
+	if err := c.Plugins.OnMetrics(context.Background(), outPut); err != nil {
+		return outPut, err
+	}
+
+	return outPut, nil
+}
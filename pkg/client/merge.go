@@ -0,0 +1,50 @@
+package client
+
+import "time"
+
+// MergeRequestTimings aggregates the raw, un-aggregated timings gathered
+// across every worker in a distributed run into a single ResultMetrics,
+// using the same percentile math Coordinate uses for a single-process
+// run. baseURL, totalRequests and elapsed describe the run as a whole.
+func MergeRequestTimings(baseURL string, timings []RequestTiming, totalRequests int, elapsed time.Duration) ResultMetrics {
+	var dnsDur []float64
+	var tcpDur []float64
+	var serverDur []float64
+	var transferDur []float64
+	var statusCodes []int
+
+	for _, item := range timings {
+		if item.DNSLookup != 0 {
+			dnsDur = append(dnsDur, item.DNSLookup)
+		}
+		if item.TCPConn < 1000 {
+			tcpDur = append(tcpDur, item.TCPConn)
+		}
+		serverDur = append(serverDur, item.ServerProcessing)
+		transferDur = append(transferDur, item.ContentTransfer)
+		statusCodes = append(statusCodes, item.StatusCode)
+	}
+
+	return ResultMetrics{
+		BaseURL:           baseURL,
+		FailedRequests:    failedRequests(statusCodes),
+		RequestsPerSecond: requestsPerSecond(totalRequests, elapsed),
+		TotalRequests:     totalRequests,
+		DNSMedian:         calcMedian(dnsDur),
+		TCPStats: tcpStats{
+			TCPMean:   calcMean(tcpDur),
+			TCPMedian: calcMedian(tcpDur),
+			TCP95p:    stringToFloat(calc95Percentile(tcpDur)),
+		},
+		ProcessingStats: serverProcessingStats{
+			ServerProcessingMean:   calcMean(serverDur),
+			ServerProcessingMedian: calcMedian(serverDur),
+			ServerProcessing95p:    stringToFloat(calc95Percentile(serverDur)),
+		},
+		ContentStats: contentTransfer{
+			ContentTransferMean:   calcMean(transferDur),
+			ContentTransferMedian: calcMedian(transferDur),
+			ContentTransfer95p:    stringToFloat(calc95Percentile(transferDur)),
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	push "go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+// DefaultOTLPBuckets are the histogram bucket boundaries, in
+// milliseconds, used by an OTLPSink when its OTLPConfig doesn't specify
+// its own.
+var DefaultOTLPBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// OTLPConfig configures an OTLPSink.
+type OTLPConfig struct {
+	Endpoint string
+	Headers  map[string]string
+	Insecure bool
+	// Buckets are the histogram bucket boundaries, in milliseconds, for
+	// the TCP/processing/content-transfer aggregate histograms. Defaults
+	// to DefaultOTLPBuckets.
+	Buckets []float64
+}
+
+// OTLPSink exports a run's results to an OpenTelemetry Collector over
+// OTLP: the final ResultMetrics as aggregate histograms (Export), and
+// every completed request as a span tagged with method, URL path, status code
+// and attempt number (Stream). It implements both MetricSink and
+// StreamingSink, so it composes with cassowary's other sinks.
+type OTLPSink struct {
+	exporter   *otlp.Exporter
+	controller *push.Controller
+	tracer     trace.Tracer
+	buckets    []float64
+}
+
+// NewOTLPSink dials the collector at cfg.Endpoint and returns a sink
+// ready to Export and Stream.
+func NewOTLPSink(cfg OTLPConfig) (*OTLPSink, error) {
+	opts := []otlp.ExporterOption{otlp.WithAddress(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlp.WithHeaders(cfg.Headers))
+	}
+
+	exporter, err := otlp.NewExporter(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := cfg.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultOTLPBuckets
+	}
+
+	processor := basic.New(simple.NewWithHistogramDistribution(buckets), exporter)
+	controller := push.New(processor, exporter)
+	controller.Start()
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	return &OTLPSink{
+		exporter:   exporter,
+		controller: controller,
+		tracer:     tracerProvider.Tracer("cassowary"),
+		buckets:    buckets,
+	}, nil
+}
+
+// Name identifies the sink in logs and error messages.
+func (s *OTLPSink) Name() string { return "otlp" }
+
+// Export records the run's mean/median/95th-percentile TCP, server
+// processing and content transfer durations as OTel histogram
+// observations, then forces a final synchronous flush to the collector.
+// ResultMetrics only carries these pre-aggregated statistics (not the raw
+// per-request samples), so each histogram receives one observation per
+// statistic rather than one per request.
+func (s *OTLPSink) Export(ctx context.Context, metrics ResultMetrics) error {
+	meter := s.controller.MeterProvider().Meter("cassowary")
+
+	tcpHist, err := meter.NewFloat64ValueRecorder("cassowary.tcp_connect")
+	if err != nil {
+		return err
+	}
+	processingHist, err := meter.NewFloat64ValueRecorder("cassowary.server_processing")
+	if err != nil {
+		return err
+	}
+	transferHist, err := meter.NewFloat64ValueRecorder("cassowary.content_transfer")
+	if err != nil {
+		return err
+	}
+
+	urlLabel := label.String("url", metrics.BaseURL)
+	recordStats := func(hist metric.Float64ValueRecorder, mean, median, p95 float64) {
+		hist.Record(ctx, mean, urlLabel, label.String("stat", "mean"))
+		hist.Record(ctx, median, urlLabel, label.String("stat", "median"))
+		hist.Record(ctx, p95, urlLabel, label.String("stat", "p95"))
+	}
+
+	recordStats(tcpHist, metrics.TCPStats.TCPMean, metrics.TCPStats.TCPMedian, metrics.TCPStats.TCP95p)
+	recordStats(processingHist, metrics.ProcessingStats.ServerProcessingMean, metrics.ProcessingStats.ServerProcessingMedian, metrics.ProcessingStats.ServerProcessing95p)
+	recordStats(transferHist, metrics.ContentStats.ContentTransferMean, metrics.ContentStats.ContentTransferMedian, metrics.ContentStats.ContentTransfer95p)
+
+	// Stop() performs one final synchronous checkpoint+export, which is
+	// the natural hook for "export once at the end of a run" rather than
+	// relying on the push controller's periodic ticker.
+	s.controller.Stop()
+	return s.exporter.Shutdown(ctx)
+}
+
+// Stream emits one span per request, tagged with method, URL path,
+// status code and attempt number, until results is closed.
+func (s *OTLPSink) Stream(ctx context.Context, results <-chan RequestResult) {
+	for result := range results {
+		path := result.URL
+		if u, err := url.Parse(result.URL); err == nil {
+			path = u.Path
+		}
+		_, span := s.tracer.Start(ctx, "cassowary.request")
+		span.SetAttributes(
+			label.String("http.method", result.Method),
+			label.String("http.target", path),
+			label.Int("http.status_code", result.StatusCode),
+			label.Int("cassowary.attempt", result.Attempt),
+		)
+		if result.Err != "" {
+			span.SetAttributes(label.String("cassowary.error", result.Err))
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,380 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+// ProtocolVersion is bumped whenever the Plugin RPC surface changes in a
+// backwards-incompatible way. A plugin binary built against an older
+// version is rejected at handshake time with a clear error instead of
+// failing confusingly once the run is underway.
+const ProtocolVersion = 2
+
+// Handshake is shared between the cassowary host process and plugin
+// binaries so both sides agree on the magic cookie and protocol version
+// before any RPC traffic flows.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  ProtocolVersion,
+	MagicCookieKey:   "CASSOWARY_PLUGIN",
+	MagicCookieValue: "cassowary",
+}
+
+// pluginSet is the set of plugin kinds a cassowary binary of the current
+// ProtocolVersion can dispense. There is only one kind today, "plugin",
+// which NewPluginManager dispenses by name; keeping the set as a map is
+// what lets new plugin kinds be added without changing the handshake.
+var pluginSet = plugin.PluginSet{
+	"plugin": &CassowaryPlugin{},
+}
+
+// versionedPlugins maps every plugin ProtocolVersion this host serves to
+// its PluginSet: go-plugin negotiates the highest version both sides
+// share and picks its PluginSet from here, so a version that isn't in
+// this map fails the handshake with an incompatible-version error rather
+// than a confusing failure once the run is underway. Only the current
+// version is served today, so a plugin binary built against an older
+// ProtocolVersion is rejected; add an entry here when ProtocolVersion is
+// bumped and an older plugin ABI still needs to be supported.
+var versionedPlugins = map[int]plugin.PluginSet{
+	ProtocolVersion: pluginSet,
+}
+
+// RequestResult is what a plugin observes after a single HTTP round trip.
+type RequestResult struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Latency    float64 // milliseconds
+	Attempt    int
+	Err        string
+}
+
+// Plugin is implemented by anything that wants to observe or influence a
+// cassowary run. BeforeRequest can mutate the outgoing request (inject
+// headers, rewrite the URL, sign it); the rest are observational.
+type Plugin interface {
+	OnStart(ctx context.Context, baseURL string) error
+	BeforeRequest(ctx context.Context, req *http.Request) error
+	AfterResponse(ctx context.Context, result RequestResult) error
+	OnMetrics(ctx context.Context, metrics ResultMetrics) error
+	OnStop(ctx context.Context) error
+}
+
+// NoopPlugin implements Plugin with no-ops. Real plugins embed it and
+// override only the hooks they care about.
+type NoopPlugin struct{}
+
+// OnStart is a no-op.
+func (NoopPlugin) OnStart(_ context.Context, _ string) error { return nil }
+
+// BeforeRequest is a no-op.
+func (NoopPlugin) BeforeRequest(_ context.Context, _ *http.Request) error { return nil }
+
+// AfterResponse is a no-op.
+func (NoopPlugin) AfterResponse(_ context.Context, _ RequestResult) error { return nil }
+
+// OnMetrics is a no-op.
+func (NoopPlugin) OnMetrics(_ context.Context, _ ResultMetrics) error { return nil }
+
+// OnStop is a no-op.
+func (NoopPlugin) OnStop(_ context.Context) error { return nil }
+
+// wireRequest is the net/rpc-safe representation of an *http.Request.
+// http.Request itself can't cross an RPC boundary (its Body is a live
+// io.Reader), so BeforeRequest sends the method, URL, headers and body
+// across in this form and copies the URL and headers of the (possibly
+// plugin-modified) result back onto the real request. A method or body
+// changed by the plugin is not applied.
+type wireRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// CassowaryPlugin is the go-plugin glue: it satisfies plugin.Plugin on
+// both sides of the process boundary. Impl is set by the plugin binary
+// (server side); the host only ever uses the Client half.
+//
+// SCOPE DECISION: this deliberately implements plugin.Plugin (net/rpc/
+// gob) rather than plugin.GRPCPlugin, even though cross-language plugin
+// support was the stated motivation for this feature. Sign-off: accepted
+// for now — this build environment has no protoc and no way to fetch
+// one, so there was never a way to generate or verify a .proto-based
+// service here, and net/rpc is otherwise this repo's established RPC
+// idiom. Consequence: plugin binaries must be written in Go; the
+// cross-language goal is not delivered. Revisit once a protoc-capable
+// environment is available. Migrating to plugin.GRPCPlugin then is
+// additive: Plugin, NoopPlugin, and PluginManager's public API don't
+// need to change, only this struct's Server/Client methods and a
+// generated pb.go.
+type CassowaryPlugin struct {
+	Impl Plugin
+}
+
+// Server returns the net/rpc server half, run inside the plugin process.
+func (p *CassowaryPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
+	return &pluginRPCServer{impl: p.Impl}, nil
+}
+
+// Client returns the net/rpc client half, run inside the cassowary host
+// process.
+func (*CassowaryPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &pluginRPCClient{client: c}, nil
+}
+
+// pluginRPCClient implements Plugin by forwarding every call over net/rpc
+// to the plugin process.
+type pluginRPCClient struct {
+	client *rpc.Client
+}
+
+func (p *pluginRPCClient) OnStart(_ context.Context, baseURL string) error {
+	var resp string
+	if err := p.client.Call("Plugin.OnStart", baseURL, &resp); err != nil {
+		return err
+	}
+	return errFromString(resp)
+}
+
+func (p *pluginRPCClient) BeforeRequest(_ context.Context, req *http.Request) error {
+	var body []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		body = b
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+
+	args := wireRequest{Method: req.Method, URL: req.URL.String(), Header: req.Header, Body: body}
+	var resp wireRequest
+	if err := p.client.Call("Plugin.BeforeRequest", args, &resp); err != nil {
+		return err
+	}
+
+	if resp.URL != "" {
+		newURL, err := req.URL.Parse(resp.URL)
+		if err != nil {
+			return err
+		}
+		req.URL = newURL
+	}
+	if resp.Header != nil {
+		req.Header = resp.Header
+	}
+	return nil
+}
+
+func (p *pluginRPCClient) AfterResponse(_ context.Context, result RequestResult) error {
+	var resp string
+	if err := p.client.Call("Plugin.AfterResponse", result, &resp); err != nil {
+		return err
+	}
+	return errFromString(resp)
+}
+
+func (p *pluginRPCClient) OnMetrics(_ context.Context, metrics ResultMetrics) error {
+	var resp string
+	if err := p.client.Call("Plugin.OnMetrics", metrics, &resp); err != nil {
+		return err
+	}
+	return errFromString(resp)
+}
+
+func (p *pluginRPCClient) OnStop(_ context.Context) error {
+	var resp string
+	if err := p.client.Call("Plugin.OnStop", new(interface{}), &resp); err != nil {
+		return err
+	}
+	return errFromString(resp)
+}
+
+// pluginRPCServer is the net/rpc server a plugin binary runs; it adapts
+// the wire calls onto a real Plugin implementation.
+type pluginRPCServer struct {
+	impl Plugin
+}
+
+func (s *pluginRPCServer) OnStart(baseURL string, resp *string) error {
+	*resp = errToString(s.impl.OnStart(context.Background(), baseURL))
+	return nil
+}
+
+func (s *pluginRPCServer) BeforeRequest(args wireRequest, resp *wireRequest) error {
+	req, err := http.NewRequest(args.Method, args.URL, bytes.NewReader(args.Body))
+	if err != nil {
+		return err
+	}
+	req.Header = args.Header
+
+	if err := s.impl.BeforeRequest(context.Background(), req); err != nil {
+		return err
+	}
+
+	resp.Method = req.Method
+	resp.URL = req.URL.String()
+	resp.Header = req.Header
+	return nil
+}
+
+func (s *pluginRPCServer) AfterResponse(result RequestResult, resp *string) error {
+	*resp = errToString(s.impl.AfterResponse(context.Background(), result))
+	return nil
+}
+
+func (s *pluginRPCServer) OnMetrics(metrics ResultMetrics, resp *string) error {
+	*resp = errToString(s.impl.OnMetrics(context.Background(), metrics))
+	return nil
+}
+
+func (s *pluginRPCServer) OnStop(_ interface{}, resp *string) error {
+	*resp = errToString(s.impl.OnStop(context.Background()))
+	return nil
+}
+
+func errToString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func errFromString(s string) error {
+	if s == "" {
+		return nil
+	}
+	return errors.New(s)
+}
+
+// loadedPlugin pairs a running plugin.Client with the Plugin stub the
+// host talks to, so the manager can kill it again on Stop.
+type loadedPlugin struct {
+	name   string
+	client *plugin.Client
+	impl   Plugin
+}
+
+// PluginManager discovers, launches, and fans hooks out to every plugin
+// binary found in a directory. Plugins are kept alive for the duration
+// of the run rather than spawned per-call.
+type PluginManager struct {
+	strict  bool
+	plugins []*loadedPlugin
+}
+
+// NewPluginManager launches every executable file in dir as a cassowary
+// plugin. With strict set, an error from a plugin's OnStart or OnMetrics
+// hook is returned from the Coordinate* call, while an error from
+// BeforeRequest or AfterResponse fails only the request it came from and
+// the run continues. Without strict, every plugin error is logged and
+// the run continues.
+func NewPluginManager(dir string, strict bool) (*PluginManager, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read plugin dir %s: %s", dir, err)
+	}
+
+	m := &PluginManager{strict: strict}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Mode()&0111 == 0 {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		logger := hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin." + entry.Name(),
+			Output: os.Stderr,
+			Level:  hclog.Warn,
+		})
+
+		c := plugin.NewClient(&plugin.ClientConfig{
+			HandshakeConfig:  Handshake,
+			VersionedPlugins: versionedPlugins,
+			Cmd:              exec.Command(path),
+			Logger:           logger,
+		})
+
+		rpcClient, err := c.Client()
+		if err != nil {
+			c.Kill()
+			return nil, fmt.Errorf("could not build rpc client for plugin %s: %s", entry.Name(), err)
+		}
+
+		raw, err := rpcClient.Dispense("plugin")
+		if err != nil {
+			c.Kill()
+			return nil, fmt.Errorf("could not dispense plugin %s: %s", entry.Name(), err)
+		}
+
+		m.plugins = append(m.plugins, &loadedPlugin{
+			name:   entry.Name(),
+			client: c,
+			impl:   raw.(Plugin),
+		})
+	}
+
+	return m, nil
+}
+
+func (m *PluginManager) each(hook func(Plugin) error) error {
+	if m == nil {
+		return nil
+	}
+	for _, p := range m.plugins {
+		if err := hook(p.impl); err != nil {
+			wrapped := fmt.Errorf("plugin %s: %s", p.name, err)
+			if m.strict {
+				return wrapped
+			}
+			fmt.Fprintln(os.Stderr, wrapped)
+		}
+	}
+	return nil
+}
+
+// OnStart fans out to every plugin before the first request is sent.
+func (m *PluginManager) OnStart(ctx context.Context, baseURL string) error {
+	return m.each(func(p Plugin) error { return p.OnStart(ctx, baseURL) })
+}
+
+// BeforeRequest fans out to every plugin, in order, letting each mutate
+// req in turn.
+func (m *PluginManager) BeforeRequest(ctx context.Context, req *http.Request) error {
+	return m.each(func(p Plugin) error { return p.BeforeRequest(ctx, req) })
+}
+
+// AfterResponse fans out to every plugin after a request completes.
+func (m *PluginManager) AfterResponse(ctx context.Context, result RequestResult) error {
+	return m.each(func(p Plugin) error { return p.AfterResponse(ctx, result) })
+}
+
+// OnMetrics fans out the final aggregated metrics to every plugin, e.g.
+// for custom sinks such as Datadog or Kafka.
+func (m *PluginManager) OnMetrics(ctx context.Context, metrics ResultMetrics) error {
+	return m.each(func(p Plugin) error { return p.OnMetrics(ctx, metrics) })
+}
+
+// Stop tells every plugin the run has ended and kills its process.
+func (m *PluginManager) Stop(ctx context.Context) error {
+	err := m.each(func(p Plugin) error { return p.OnStop(ctx) })
+	if m != nil {
+		for _, p := range m.plugins {
+			p.client.Kill()
+		}
+	}
+	return err
+}
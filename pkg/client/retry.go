@@ -0,0 +1,59 @@
+package client
+
+import (
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how Coordinate retries a request that failed in a
+// way matched by RetryOn. MaxRetries of 0 (the zero value) disables
+// retries entirely, preserving the old behavior of counting every
+// failure straight into FailedRequests.
+type RetryPolicy struct {
+	MaxRetries  int
+	RetryOn     []string
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Jitter      bool
+}
+
+// matchesRetryOn reports whether a request outcome should be retried
+// under policy.RetryOn, which is a set of "5xx", "connreset" and
+// "timeout" keywords.
+func matchesRetryOn(retryOn []string, statusCode int, err error) bool {
+	for _, kind := range retryOn {
+		switch kind {
+		case "5xx":
+			if statusCode >= 500 && statusCode < 600 {
+				return true
+			}
+		case "connreset":
+			if err != nil && strings.Contains(err.Error(), "connection reset") {
+				return true
+			}
+		case "timeout":
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backoffDuration computes a full-jitter exponential backoff delay for
+// the given (zero-indexed) retry attempt: sleep = rand(0, min(cap,
+// base*2^attempt)). With Jitter disabled, min(cap, base*2^attempt)
+// itself is returned with no randomization.
+func backoffDuration(policy RetryPolicy, attempt int) time.Duration {
+	d := policy.BackoffBase << uint(attempt)
+	if d <= 0 || d > policy.BackoffMax {
+		d = policy.BackoffMax
+	}
+
+	if !policy.Jitter {
+		return d
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
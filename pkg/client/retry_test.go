@@ -0,0 +1,145 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testMatchesRetryOn = []struct {
+	retryOn    []string
+	statusCode int
+	err        error
+	expected   bool
+}{
+	{[]string{"5xx"}, 503, nil, true},
+	{[]string{"5xx"}, 200, nil, false},
+	{[]string{"5xx"}, 404, nil, false},
+	{[]string{"connreset"}, 0, errors.New("read: connection reset by peer"), true},
+	{[]string{"connreset"}, 0, errors.New("some other error"), false},
+	{nil, 503, nil, false},
+}
+
+func TestMatchesRetryOn(t *testing.T) {
+	for _, tt := range testMatchesRetryOn {
+		got := matchesRetryOn(tt.retryOn, tt.statusCode, tt.err)
+		if got != tt.expected {
+			t.Errorf("matchesRetryOn(%v, %d, %v) = %v, want %v", tt.retryOn, tt.statusCode, tt.err, got, tt.expected)
+		}
+	}
+}
+
+func TestBackoffDurationNoJitter(t *testing.T) {
+	policy := RetryPolicy{BackoffBase: 100 * time.Millisecond, BackoffMax: 5 * time.Second}
+
+	if got := backoffDuration(policy, 0); got != 100*time.Millisecond {
+		t.Errorf("attempt 0: got %s, want 100ms", got)
+	}
+	if got := backoffDuration(policy, 2); got != 400*time.Millisecond {
+		t.Errorf("attempt 2: got %s, want 400ms", got)
+	}
+	if got := backoffDuration(policy, 10); got != 5*time.Second {
+		t.Errorf("attempt 10: got %s, want capped at 5s", got)
+	}
+}
+
+func TestBackoffDurationJitter(t *testing.T) {
+	policy := RetryPolicy{BackoffBase: 100 * time.Millisecond, BackoffMax: 5 * time.Second, Jitter: true}
+
+	for i := 0; i < 20; i++ {
+		got := backoffDuration(policy, 3)
+		if got < 0 || got > 800*time.Millisecond {
+			t.Fatalf("jittered backoff out of range: %s", got)
+		}
+	}
+}
+
+// TestCoordinateRetrySucceedsAfterRetries exercises a full retry-then-
+// succeed path through Coordinate, not just the pure-function retry
+// helpers above: a request that fails twice with a retryable 503 and
+// succeeds on its third attempt should be reported as one successful
+// request whose RetryStats record 3 attempts-to-success and a nonzero
+// latency, not as a failure.
+func TestCoordinateRetrySucceedsAfterRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cass := Cassowary{
+		BaseURL:               srv.URL,
+		ConcurrencyLevel:      1,
+		Requests:              1,
+		RetryPolicy:           RetryPolicy{MaxRetries: 3, RetryOn: []string{"5xx"}, BackoffBase: time.Millisecond, BackoffMax: time.Millisecond},
+		DisableKeepAlive:      true,
+		DisableTerminalOutput: true,
+	}
+
+	metrics, err := cass.Coordinate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metrics.FailedRequests != 0 {
+		t.Errorf("expected the eventually-successful request not to count as failed, got %d failed", metrics.FailedRequests)
+	}
+	if metrics.RetryStats == nil {
+		t.Fatal("expected RetryStats to be set")
+	}
+	if metrics.RetryStats.Exhausted != 0 {
+		t.Errorf("expected 0 exhausted, got %d", metrics.RetryStats.Exhausted)
+	}
+	if metrics.RetryStats.AttemptsMean != 3 {
+		t.Errorf("expected 3 attempts-to-success, got %v", metrics.RetryStats.AttemptsMean)
+	}
+	if metrics.RetryStats.LatencyMean <= 0 {
+		t.Errorf("expected a positive latency recorded for the retried request, got %v", metrics.RetryStats.LatencyMean)
+	}
+}
+
+// TestCoordinateRetryExhaustedExcludedFromAttempts confirms a request
+// that exhausts every retry without succeeding is counted in Exhausted
+// but does not pollute AttemptsMean, which is documented as
+// attempts-to-success.
+func TestCoordinateRetryExhaustedExcludedFromAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cass := Cassowary{
+		BaseURL:               srv.URL,
+		ConcurrencyLevel:      1,
+		Requests:              1,
+		RetryPolicy:           RetryPolicy{MaxRetries: 2, RetryOn: []string{"5xx"}, BackoffBase: time.Millisecond, BackoffMax: time.Millisecond},
+		DisableKeepAlive:      true,
+		DisableTerminalOutput: true,
+	}
+
+	metrics, err := cass.Coordinate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metrics.FailedRequests != 1 {
+		t.Errorf("expected the exhausted request to count as failed, got %d failed", metrics.FailedRequests)
+	}
+	if metrics.RetryStats == nil {
+		t.Fatal("expected RetryStats to be set")
+	}
+	if metrics.RetryStats.Exhausted != 1 {
+		t.Errorf("expected 1 exhausted, got %d", metrics.RetryStats.Exhausted)
+	}
+	if metrics.RetryStats.AttemptsMean != 0 {
+		t.Errorf("expected the exhausted request excluded from attempts-to-success, got mean %v", metrics.RetryStats.AttemptsMean)
+	}
+}
@@ -0,0 +1,71 @@
+package scenario
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// StepResult is what a single step execution produced, used by callers
+// to aggregate per-step timing and failure counts.
+type StepResult struct {
+	Name       string
+	StatusCode int
+	Latency    float64 // milliseconds
+	Passed     bool
+}
+
+// Do executes one step against httpClient on behalf of vu: it
+// interpolates the method/URL/headers/body, sends the request using the
+// VU's cookie jar, runs any response extractions, sleeps for ThinkTime,
+// and reports whether the expected status (if any) was met.
+func (s Step) Do(httpClient *http.Client, vu *VU) (StepResult, error) {
+	url := vu.Interpolate(s.URL)
+	body := vu.Interpolate(s.Body)
+
+	req, err := http.NewRequest(s.Method, url, bytes.NewBufferString(body))
+	if err != nil {
+		return StepResult{}, err
+	}
+	for key, value := range s.Headers {
+		req.Header.Set(key, vu.Interpolate(value))
+	}
+
+	client := &http.Client{
+		Transport: httpClient.Transport,
+		Timeout:   httpClient.Timeout,
+		Jar:       vu.Jar,
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return StepResult{}, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return StepResult{}, err
+	}
+
+	latency := float64(time.Since(start) / time.Millisecond)
+
+	if err := vu.Extract(respBody, s.Extract); err != nil {
+		return StepResult{}, err
+	}
+
+	if s.ThinkTime > 0 {
+		time.Sleep(s.ThinkTime)
+	}
+
+	passed := s.ExpectStatus == 0 || resp.StatusCode == s.ExpectStatus
+
+	return StepResult{
+		Name:       s.Name,
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+		Passed:     passed,
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Package scenario parses and drives multi-step load test scenarios,
+// turning cassowary from a single-endpoint hammer into a workflow load
+// tester: an ordered list of HTTP steps, each able to save data from the
+// response into per-virtual-user variables that later steps interpolate.
+package scenario
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Scenario is an ordered list of steps loaded from a YAML scenario file.
+type Scenario struct {
+	Name  string `yaml:"name"`
+	Steps []Step `yaml:"steps"`
+}
+
+// Step is a single request in a scenario: a method/URL/headers/body
+// (each of which may reference `{{ .var }}` placeholders), an optional
+// expected status, and a set of JSONPath-lite extractions to save into
+// the virtual user's variable scope for later steps.
+type Step struct {
+	Name         string            `yaml:"name"`
+	Method       string            `yaml:"method"`
+	URL          string            `yaml:"url"`
+	Headers      map[string]string `yaml:"headers"`
+	Body         string            `yaml:"body"`
+	ExpectStatus int               `yaml:"expect_status"`
+	Extract      map[string]string `yaml:"extract"`
+	ThinkTime    time.Duration     `yaml:"think_time"`
+	// Branch groups steps that compete for a single slot in the
+	// sequence: of all steps sharing a Branch name, one is picked per
+	// iteration with probability proportional to Weight.
+	Branch string `yaml:"branch"`
+	Weight int    `yaml:"weight"`
+}
+
+// Sequence resolves weighted branching into one concrete, ordered list
+// of steps for a single scenario iteration. Steps with no Branch always
+// run; of the steps sharing a Branch name, exactly one is chosen with
+// probability proportional to its Weight.
+func (s *Scenario) Sequence() []Step {
+	var out []Step
+	seenBranch := make(map[string]bool)
+
+	for i, step := range s.Steps {
+		if step.Branch == "" {
+			out = append(out, step)
+			continue
+		}
+		if seenBranch[step.Branch] {
+			continue
+		}
+		seenBranch[step.Branch] = true
+
+		var candidates []Step
+		total := 0
+		for _, other := range s.Steps[i:] {
+			if other.Branch != step.Branch {
+				continue
+			}
+			candidates = append(candidates, other)
+			total += weightOf(other)
+		}
+
+		target := rand.Intn(total)
+		for _, candidate := range candidates {
+			weight := weightOf(candidate)
+			if target < weight {
+				out = append(out, candidate)
+				break
+			}
+			target -= weight
+		}
+	}
+
+	return out
+}
+
+func weightOf(s Step) int {
+	if s.Weight <= 0 {
+		return 1
+	}
+	return s.Weight
+}
+
+// Parse loads and validates a scenario file. Only YAML is understood
+// today; an .hcl extension is rejected with a clear error rather than
+// silently misparsed, since HCL scenario support isn't implemented yet.
+func Parse(path string) (*Scenario, error) {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", "":
+	case ".hcl":
+		return nil, fmt.Errorf("scenario: %s: HCL scenario files are not supported yet, use YAML", path)
+	default:
+		return nil, fmt.Errorf("scenario: %s: unrecognized scenario file extension, use .yaml or .yml", path)
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scn Scenario
+	if err := yaml.Unmarshal(raw, &scn); err != nil {
+		return nil, err
+	}
+
+	for i, step := range scn.Steps {
+		if step.Method == "" {
+			scn.Steps[i].Method = "GET"
+		}
+		if step.Name == "" {
+			scn.Steps[i].Name = step.URL
+		}
+	}
+
+	return &scn, nil
+}
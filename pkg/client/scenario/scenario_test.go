@@ -0,0 +1,104 @@
+package scenario
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSequenceNoBranching(t *testing.T) {
+	scn := &Scenario{
+		Steps: []Step{
+			{Name: "login"},
+			{Name: "list"},
+		},
+	}
+
+	seq := scn.Sequence()
+	if len(seq) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(seq))
+	}
+	if seq[0].Name != "login" || seq[1].Name != "list" {
+		t.Fatalf("unexpected sequence: %+v", seq)
+	}
+}
+
+func TestSequenceBranching(t *testing.T) {
+	scn := &Scenario{
+		Steps: []Step{
+			{Name: "a", Branch: "checkout", Weight: 1000},
+			{Name: "b", Branch: "checkout", Weight: 1},
+		},
+	}
+
+	seenA := false
+	for i := 0; i < 20; i++ {
+		seq := scn.Sequence()
+		if len(seq) != 1 {
+			t.Fatalf("expected exactly one branch step, got %d", len(seq))
+		}
+		if seq[0].Name != "a" && seq[0].Name != "b" {
+			t.Fatalf("unexpected step picked: %q", seq[0].Name)
+		}
+		if seq[0].Name == "a" {
+			seenA = true
+		}
+	}
+	if !seenA {
+		t.Fatal("expected the heavily-weighted step 'a' to be picked at least once in 20 tries")
+	}
+}
+
+func TestParse(t *testing.T) {
+	content := `
+name: example
+steps:
+  - url: http://example.com
+  - name: second
+    method: POST
+`
+	f, err := ioutil.TempFile("", "scenario-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	scn, err := Parse(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if scn.Name != "example" {
+		t.Fatalf("expected name 'example', got %q", scn.Name)
+	}
+	if len(scn.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(scn.Steps))
+	}
+	if scn.Steps[0].Method != "GET" {
+		t.Fatalf("expected default method GET, got %q", scn.Steps[0].Method)
+	}
+	if scn.Steps[0].Name != "http://example.com" {
+		t.Fatalf("expected default name to fall back to URL, got %q", scn.Steps[0].Name)
+	}
+	if scn.Steps[1].Method != "POST" {
+		t.Fatalf("expected explicit method POST, got %q", scn.Steps[1].Method)
+	}
+}
+
+func TestParseRejectsHCL(t *testing.T) {
+	f, err := ioutil.TempFile("", "scenario-*.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	if _, err := Parse(f.Name()); err == nil {
+		t.Fatal("expected Parse to reject an .hcl file, got nil error")
+	}
+}
@@ -0,0 +1,95 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/cookiejar"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var interpolationPattern = regexp.MustCompile(`{{\s*\.(\w+)\s*}}`)
+
+// VU holds one virtual user's variable scope and cookie jar. Each VU
+// runs a scenario independently, so extracted variables never leak
+// between virtual users.
+type VU struct {
+	Vars map[string]string
+	Jar  *cookiejar.Jar
+}
+
+// NewVU creates an empty virtual user scope.
+func NewVU() (*VU, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &VU{Vars: make(map[string]string), Jar: jar}, nil
+}
+
+// Interpolate replaces every `{{ .name }}` placeholder in s with the
+// matching variable from the VU's scope. An unknown variable is left as
+// an empty string rather than failing the step.
+func (vu *VU) Interpolate(s string) string {
+	return interpolationPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := interpolationPattern.FindStringSubmatch(match)[1]
+		return vu.Vars[name]
+	})
+}
+
+// Extract runs every JSONPath-lite expression in extract against body
+// and saves the results into the VU's variable scope under the given
+// names.
+func (vu *VU) Extract(body []byte, extract map[string]string) error {
+	if len(extract) == 0 {
+		return nil
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return fmt.Errorf("extract: response body is not valid JSON: %s", err)
+	}
+
+	for name, path := range extract {
+		value, err := lookupPath(parsed, path)
+		if err != nil {
+			return fmt.Errorf("extract %s: %s", name, err)
+		}
+		vu.Vars[name] = value
+	}
+	return nil
+}
+
+// lookupPath supports a JSONPath subset: a leading "$." or "." is
+// optional, and the remainder is a dot-separated walk of object fields
+// and numeric array indices, e.g. "data.users.0.id".
+func lookupPath(data interface{}, path string) (string, error) {
+	path = strings.TrimPrefix(path, "$.")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return fmt.Sprintf("%v", data), nil
+	}
+
+	cur := data
+	for _, part := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return "", fmt.Errorf("no field %q", part)
+			}
+			cur = next
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return "", fmt.Errorf("invalid array index %q", part)
+			}
+			cur = v[idx]
+		default:
+			return "", fmt.Errorf("cannot descend into %q", part)
+		}
+	}
+
+	return fmt.Sprintf("%v", cur), nil
+}
@@ -0,0 +1,49 @@
+package scenario
+
+import "testing"
+
+func TestInterpolate(t *testing.T) {
+	vu, err := NewVU()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vu.Vars["token"] = "abc123"
+
+	got := vu.Interpolate("Bearer {{ .token }}")
+	if got != "Bearer abc123" {
+		t.Fatalf("expected 'Bearer abc123', got %q", got)
+	}
+
+	got = vu.Interpolate("{{ .missing }}")
+	if got != "" {
+		t.Fatalf("expected empty string for unknown variable, got %q", got)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	vu, err := NewVU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte(`{"data": {"users": [{"id": "u-1"}]}}`)
+	if err := vu.Extract(body, map[string]string{"userID": "data.users.0.id"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if vu.Vars["userID"] != "u-1" {
+		t.Fatalf("expected 'u-1', got %q", vu.Vars["userID"])
+	}
+}
+
+func TestExtractInvalidPath(t *testing.T) {
+	vu, err := NewVU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte(`{"data": {}}`)
+	if err := vu.Extract(body, map[string]string{"missing": "data.nope"}); err == nil {
+		t.Fatal("expected error for missing field, got nil")
+	}
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rogerwelin/cassowary/pkg/client/scenario"
+)
+
+// ScenarioStats reports per-step timing and failure counts for a
+// scenario run, one entry per distinct step name across every VU.
+type ScenarioStats struct {
+	Steps []StepStats `json:"steps"`
+}
+
+// StepStats aggregates the timings recorded for every execution of a
+// single named step.
+type StepStats struct {
+	Name   string  `json:"name"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P95    float64 `json:"95th_percentile"`
+	Failed int     `json:"failed"`
+}
+
+func (c *Cassowary) runScenarioVU(scn *scenario.Scenario, iterations int, durationMode bool, deadline time.Time, httpClient *http.Client, outPutChan chan<- scenario.StepResult) {
+	vu, err := scenario.NewVU()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	runOnce := func() {
+		for _, step := range scn.Sequence() {
+			result, err := step.Do(httpClient, vu)
+			if err != nil {
+				// A transport-level failure (timeout, connection reset, DNS
+				// failure) on one VU's step shouldn't take down every other
+				// concurrently-running VU: count it as a failed step instead
+				// of aborting the run.
+				result = scenario.StepResult{Name: step.Name, Passed: false}
+			}
+			outPutChan <- result
+		}
+	}
+
+	if durationMode {
+		for time.Now().Before(deadline) {
+			runOnce()
+		}
+		return
+	}
+
+	for i := 0; i < iterations; i++ {
+		runOnce()
+	}
+}
+
+// CoordinateScenario drives a multi-step scenario load test: scn is run
+// by ConcurrencyLevel virtual users, each with its own variable scope and
+// cookie jar, either Requests/ConcurrencyLevel times each or for Duration
+// seconds, whichever mode is configured.
+func (c *Cassowary) CoordinateScenario(scn *scenario.Scenario) (ResultMetrics, error) {
+	httpClient := &http.Client{
+		Timeout:   time.Second * time.Duration(c.Timeout),
+		Transport: &http.Transport{TLSClientConfig: c.TLSConfig},
+	}
+
+	if err := c.Plugins.OnStart(context.Background(), c.BaseURL); err != nil {
+		return ResultMetrics{}, err
+	}
+	defer c.Plugins.Stop(context.Background())
+
+	durationMode := c.Duration > 0
+	deadline := time.Now().Add(time.Duration(c.Duration) * time.Second)
+
+	// Distribute Requests across ConcurrencyLevel VUs the same way
+	// shardJob does for the cluster coordinator: base iterations per VU,
+	// remainder handed to the first VUs, rather than truncating and
+	// silently dropping iterations.
+	baseIterations, iterationRemainder := c.Requests/c.ConcurrencyLevel, c.Requests%c.ConcurrencyLevel
+
+	var wg sync.WaitGroup
+	channel := make(chan scenario.StepResult, c.ConcurrencyLevel)
+	wg.Add(c.ConcurrencyLevel)
+
+	start := time.Now()
+	for i := 0; i < c.ConcurrencyLevel; i++ {
+		iterations := baseIterations
+		if i < iterationRemainder {
+			iterations++
+		}
+		if iterations == 0 {
+			iterations = 1
+		}
+		go func(iterations int) {
+			defer wg.Done()
+			c.runScenarioVU(scn, iterations, durationMode, deadline, httpClient, channel)
+		}(iterations)
+	}
+
+	go func() {
+		wg.Wait()
+		close(channel)
+	}()
+
+	perStep := map[string][]float64{}
+	failedPerStep := map[string]int{}
+	var stepOrder []string
+	total := 0
+	failed := 0
+
+	for result := range channel {
+		total++
+		if _, ok := perStep[result.Name]; !ok {
+			stepOrder = append(stepOrder, result.Name)
+		}
+		perStep[result.Name] = append(perStep[result.Name], result.Latency)
+		if !result.Passed {
+			failed++
+			failedPerStep[result.Name]++
+		}
+	}
+	end := time.Since(start)
+
+	var stepStats []StepStats
+	for _, name := range stepOrder {
+		durations := perStep[name]
+		stepStats = append(stepStats, StepStats{
+			Name:   name,
+			Mean:   calcMean(durations),
+			Median: calcMedian(durations),
+			P95:    stringToFloat(calc95Percentile(durations)),
+			Failed: failedPerStep[name],
+		})
+	}
+
+	outPut := ResultMetrics{
+		BaseURL:           c.BaseURL,
+		TotalRequests:     total,
+		FailedRequests:    failed,
+		RequestsPerSecond: requestsPerSecond(total, end),
+		ScenarioStats:     &ScenarioStats{Steps: stepStats},
+	}
+
+	if err := c.Plugins.OnMetrics(context.Background(), outPut); err != nil {
+		return outPut, err
+	}
+
+	return outPut, nil
+}
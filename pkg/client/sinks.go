@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// MetricSink receives the aggregated ResultMetrics of a completed load
+// test. A Cassowary run can fan the same ResultMetrics out to any number
+// of sinks; see Cassowary.MetricSinks.
+type MetricSink interface {
+	Name() string
+	Export(ctx context.Context, metrics ResultMetrics) error
+}
+
+// StreamingSink is implemented by sinks that also want to observe every
+// request as it completes, rather than only the final aggregate. The
+// channel is closed once the run finishes; Stream should return soon
+// after that happens.
+type StreamingSink interface {
+	Stream(ctx context.Context, results <-chan RequestResult)
+}
+
+// MetricSinks returns every sink this run should export its final
+// ResultMetrics to: the built-in JSON/Prometheus/CloudWatch sinks implied
+// by the Cassowary's own flags, in that order, followed by ExtraSinks.
+// ExtraSinks is the extension point for anything constructed outside
+// these flags, e.g. the CLI's OTLP sink or a sink a caller embedding
+// cassowary as a library wants bolted on; plugins already receive the
+// same ResultMetrics via PluginManager.OnMetrics and can export it
+// themselves without needing a slot here.
+func (c *Cassowary) MetricSinks() []MetricSink {
+	var sinks []MetricSink
+
+	if c.ExportMetrics {
+		sinks = append(sinks, JSONFileSink{FileName: c.ExportMetricsFile})
+	}
+	if c.PromExport {
+		sinks = append(sinks, PrometheusSink{Cassowary: c})
+	}
+	if c.Cloudwatch {
+		sinks = append(sinks, CloudwatchSink{Cassowary: c})
+	}
+
+	return append(sinks, c.ExtraSinks...)
+}
+
+// JSONFileSink writes the aggregated metrics to a local JSON file.
+type JSONFileSink struct {
+	FileName string
+}
+
+// Name identifies the sink in logs and error messages.
+func (s JSONFileSink) Name() string { return "json" }
+
+// Export writes metrics as JSON to FileName, defaulting to out.json.
+func (s JSONFileSink) Export(ctx context.Context, metrics ResultMetrics) error {
+	fileName := s.FileName
+	if fileName == "" {
+		fileName = "out.json"
+	}
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	return enc.Encode(metrics)
+}
+
+// PrometheusSink pushes metrics to a Prometheus push gateway.
+type PrometheusSink struct {
+	Cassowary *Cassowary
+}
+
+// Name identifies the sink in logs and error messages.
+func (s PrometheusSink) Name() string { return "prometheus" }
+
+// Export pushes metrics via the Cassowary's configured PromURL.
+func (s PrometheusSink) Export(ctx context.Context, metrics ResultMetrics) error {
+	return s.Cassowary.PushPrometheusMetrics(metrics)
+}
+
+// CloudwatchSink publishes metrics to AWS CloudWatch.
+type CloudwatchSink struct {
+	Cassowary *Cassowary
+}
+
+// Name identifies the sink in logs and error messages.
+func (s CloudwatchSink) Name() string { return "cloudwatch" }
+
+// Export publishes metrics to CloudWatch using the default AWS session.
+func (s CloudwatchSink) Export(ctx context.Context, metrics ResultMetrics) error {
+	sess, err := session.NewSession()
+	if err != nil {
+		return err
+	}
+
+	svc := cloudwatch.New(sess)
+	_, err = s.Cassowary.PutCloudwatchMetrics(svc, metrics)
+	return err
+}
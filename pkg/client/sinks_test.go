@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONFileSinkExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassowary-sink-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "metrics.json")
+	sink := JSONFileSink{FileName: fileName}
+
+	metrics := ResultMetrics{
+		BaseURL:       "http://example.com",
+		TotalRequests: 10,
+	}
+
+	if err := sink.Export(context.Background(), metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got ResultMetrics
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.BaseURL != metrics.BaseURL || got.TotalRequests != metrics.TotalRequests {
+		t.Errorf("got %+v, want %+v", got, metrics)
+	}
+}
+
+type fakeSink struct {
+	name string
+}
+
+func (f fakeSink) Name() string { return f.name }
+
+func (f fakeSink) Export(_ context.Context, _ ResultMetrics) error { return nil }
+
+func TestMetricSinksComposition(t *testing.T) {
+	cass := &Cassowary{
+		ExportMetrics: true,
+		PromExport:    true,
+		ExtraSinks:    []MetricSink{fakeSink{name: "extra"}},
+	}
+
+	sinks := cass.MetricSinks()
+	if len(sinks) != 3 {
+		t.Fatalf("got %d sinks, want 3", len(sinks))
+	}
+	if sinks[0].Name() != "json" || sinks[1].Name() != "prometheus" || sinks[2].Name() != "extra" {
+		t.Errorf("unexpected sink order: %v, %v, %v", sinks[0].Name(), sinks[1].Name(), sinks[2].Name())
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/schollz/progressbar"
+)
+
+// Cassowary is the main struct with bootstraps the load test
+type Cassowary struct {
+	FileMode              bool
+	IsTLS                 bool
+	BaseURL               string
+	ConcurrencyLevel      int
+	Requests              int
+	Duration              int
+	ExportMetrics         bool
+	ExportMetricsFile     string
+	PromExport            bool
+	Cloudwatch            bool
+	TLSConfig             *tls.Config
+	PromURL               string
+	RequestHeader         []string
+	URLPaths              []string
+	DisableTerminalOutput bool
+	DisableKeepAlive      bool
+	Client                *http.Client
+	Bar                   *progressbar.ProgressBar
+	Timeout               int
+	HTTPMethod            string
+	Data                  []byte
+	Plugins               *PluginManager
+	StrictPlugins         bool
+	WSMessage             []byte
+	WSMessageFile         string
+	WSMessageCount        int
+	WSSendInterval        time.Duration
+	// RawOutput, if set, receives every completed request's raw,
+	// un-aggregated timing in addition to the usual aggregation done by
+	// Coordinate. pkg/cluster workers set this so a coordinator can merge
+	// timings across an entire distributed run before computing
+	// percentiles.
+	RawOutput   chan<- RequestTiming
+	RetryPolicy RetryPolicy
+	// ExtraSinks are exported to in addition to the built-in
+	// JSON/Prometheus/CloudWatch sinks implied by ExportMetrics/
+	// PromExport/Cloudwatch. See Cassowary.MetricSinks.
+	ExtraSinks []MetricSink
+	// Events, if set, receives every completed request as a RequestResult
+	// so a StreamingSink (e.g. an OTLPSink) can emit per-request spans
+	// live instead of only seeing the final aggregate. Cassowary only
+	// sends on Events and never closes it; whoever created the channel
+	// closes it once the run has returned.
+	Events chan<- RequestResult
+}
+
+// RequestTiming is the raw per-phase timing for a single request. It
+// mirrors the internal durationMetrics shape but is exported so
+// pkg/cluster can carry it across a worker/coordinator RPC boundary.
+type RequestTiming struct {
+	DNSLookup        float64
+	TCPConn          float64
+	TLSHandshake     float64
+	ServerProcessing float64
+	ContentTransfer  float64
+	StatusCode       int
+}
+
+// ResultMetrics are the aggregated metrics after the load test
+type ResultMetrics struct {
+	BaseURL           string                `json:"base_url"`
+	TotalRequests     int                   `json:"total_requests"`
+	FailedRequests    int                   `json:"failed_requests"`
+	RequestsPerSecond float64               `json:"requests_per_second"`
+	DNSMedian         float64               `json:"dns_median"`
+	TCPStats          tcpStats              `json:"tcp_connect"`
+	ProcessingStats   serverProcessingStats `json:"server_processing"`
+	ContentStats      contentTransfer       `json:"content_transfer"`
+	WebSocketStats    *WebSocketStats       `json:"websocket,omitempty"`
+	HTTP2Stats        *HTTP2Stats           `json:"http2,omitempty"`
+	ScenarioStats     *ScenarioStats        `json:"scenario,omitempty"`
+	RetryStats        *RetryStats           `json:"retries,omitempty"`
+}
+
+// RetryStats reports how many attempts requests needed to succeed under
+// a RetryPolicy: mean/median/p95 attempts-to-success (requests that did
+// not end in success, whether they exhausted every retry or failed in a
+// way RetryOn doesn't match, are excluded), the latency of every
+// request's final attempt, and how many requests exhausted every retry
+// without succeeding.
+type RetryStats struct {
+	AttemptsMean   float64 `json:"attempts_mean"`
+	AttemptsMedian float64 `json:"attempts_median"`
+	Attempts95p    float64 `json:"attempts_95th_percentile"`
+	LatencyMean    float64 `json:"latency_mean"`
+	LatencyMedian  float64 `json:"latency_median"`
+	Latency95p     float64 `json:"latency_95th_percentile"`
+	Exhausted      int     `json:"exhausted"`
+}
+
+type tcpStats struct {
+	TCPMean   float64 `json:"mean"`
+	TCPMedian float64 `json:"median"`
+	TCP95p    float64 `json:"95th_percentile"`
+}
+
+type serverProcessingStats struct {
+	ServerProcessingMean   float64 `json:"mean"`
+	ServerProcessingMedian float64 `json:"median"`
+	ServerProcessing95p    float64 `json:"95th_percentile"`
+}
+
+type contentTransfer struct {
+	ContentTransferMean   float64 `json:"mean"`
+	ContentTransferMedian float64 `json:"median"`
+	ContentTransfer95p    float64 `json:"95th_percentile"`
+}
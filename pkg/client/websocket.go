@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketStats captures the WebSocket-specific timings gathered by
+// CoordinateWebSocket, reported alongside the regular HTTP timing blocks.
+type WebSocketStats struct {
+	HandshakeMean      float64 `json:"handshake_mean"`
+	TimeToFirstMessage float64 `json:"time_to_first_message"`
+	MessageRTTMean     float64 `json:"message_rtt_mean"`
+	MessageRTTMedian   float64 `json:"message_rtt_median"`
+	MessageRTT95p      float64 `json:"message_rtt_95th_percentile"`
+}
+
+type wsConnMetrics struct {
+	Handshake    float64
+	FirstMessage float64
+	RTTs         []float64
+	Failed       bool
+}
+
+// runWebSocketConn drives one WebSocket connection's worth of the load
+// test. A dial/write/read error fails this connection only: it's
+// recorded as a failed connection in outPutChan rather than aborting the
+// whole run, the same way a transport error in doRequest or a scenario
+// step no longer takes down every other concurrent connection.
+func (c *Cassowary) runWebSocketConn(outPutChan chan<- wsConnMetrics) {
+	header := make(http.Header)
+	if len(c.RequestHeader) == 2 {
+		header.Add(c.RequestHeader[0], c.RequestHeader[1])
+	}
+
+	dialer := websocket.DefaultDialer
+	if c.TLSConfig != nil {
+		dialer = &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 45 * time.Second,
+			TLSClientConfig:  c.TLSConfig,
+		}
+	}
+
+	dialStart := time.Now()
+	conn, _, err := dialer.DialContext(context.Background(), c.BaseURL, header)
+	if err != nil {
+		log.Printf("unable to dial websocket %s: %v", c.BaseURL, err)
+		outPutChan <- wsConnMetrics{Failed: true}
+		return
+	}
+	defer conn.Close()
+
+	metrics := wsConnMetrics{
+		Handshake: float64(time.Since(dialStart) / time.Millisecond),
+	}
+
+	// With Duration set, send messages until the deadline rather than a
+	// fixed WSMessageCount, the same request/duration tradeoff Coordinate
+	// makes for the regular HTTP load test.
+	deadline := time.Time{}
+	if c.Duration > 0 {
+		deadline = time.Now().Add(time.Duration(c.Duration) * time.Second)
+	}
+
+	for i := 0; deadline.IsZero() && i < c.WSMessageCount || !deadline.IsZero() && time.Now().Before(deadline); i++ {
+		sent := time.Now()
+		if err := conn.WriteMessage(websocket.TextMessage, c.WSMessage); err != nil {
+			log.Printf("unable to write websocket message: %v", err)
+			metrics.Failed = true
+			break
+		}
+		if _, _, err := conn.ReadMessage(); err != nil {
+			log.Printf("unable to read websocket message: %v", err)
+			metrics.Failed = true
+			break
+		}
+
+		rtt := float64(time.Since(sent) / time.Millisecond)
+		if i == 0 {
+			metrics.FirstMessage = rtt
+		}
+		metrics.RTTs = append(metrics.RTTs, rtt)
+
+		if c.WSSendInterval > 0 {
+			time.Sleep(c.WSSendInterval)
+		}
+	}
+
+	outPutChan <- metrics
+}
+
+// CoordinateWebSocket drives a WebSocket load test: ConcurrencyLevel
+// connections are opened against BaseURL, using RequestHeader and
+// TLSConfig the same way the regular HTTP modes do, each sending
+// WSMessageCount messages (loaded from WSMessageFile, when set, or for
+// Duration seconds instead when Duration is set) WSSendInterval apart,
+// and waiting for one reply per message to measure round-trip time.
+func (c *Cassowary) CoordinateWebSocket() (ResultMetrics, error) {
+	if c.WSMessageFile != "" {
+		payload, err := ioutil.ReadFile(c.WSMessageFile)
+		if err != nil {
+			return ResultMetrics{}, err
+		}
+		c.WSMessage = payload
+	}
+	if c.WSMessageCount == 0 {
+		c.WSMessageCount = 1
+	}
+
+	if err := c.Plugins.OnStart(context.Background(), c.BaseURL); err != nil {
+		return ResultMetrics{}, err
+	}
+	defer c.Plugins.Stop(context.Background())
+
+	var wg sync.WaitGroup
+	channel := make(chan wsConnMetrics, c.ConcurrencyLevel)
+	wg.Add(c.ConcurrencyLevel)
+
+	for i := 0; i < c.ConcurrencyLevel; i++ {
+		go func() {
+			defer wg.Done()
+			c.runWebSocketConn(channel)
+		}()
+	}
+
+	wg.Wait()
+	close(channel)
+
+	var handshakes, firstMessages, rtts []float64
+	failedConns := 0
+	for item := range channel {
+		if item.Failed {
+			failedConns++
+		}
+		handshakes = append(handshakes, item.Handshake)
+		firstMessages = append(firstMessages, item.FirstMessage)
+		rtts = append(rtts, item.RTTs...)
+	}
+
+	outPut := ResultMetrics{
+		BaseURL:        c.BaseURL,
+		TotalRequests:  len(rtts) + failedConns,
+		FailedRequests: failedConns,
+		WebSocketStats: &WebSocketStats{
+			HandshakeMean:      calcMean(handshakes),
+			TimeToFirstMessage: calcMean(firstMessages),
+			MessageRTTMean:     calcMean(rtts),
+			MessageRTTMedian:   calcMedian(rtts),
+			MessageRTT95p:      stringToFloat(calc95Percentile(rtts)),
+		},
+	}
+
+	if err := c.Plugins.OnMetrics(context.Background(), outPut); err != nil {
+		return outPut, err
+	}
+
+	return outPut, nil
+}
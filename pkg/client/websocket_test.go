@@ -0,0 +1,102 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCoordinateWebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	cass := &Cassowary{
+		BaseURL:          "ws" + strings.TrimPrefix(srv.URL, "http"),
+		ConcurrencyLevel: 3,
+		WSMessageCount:   2,
+		WSMessage:        []byte("ping"),
+	}
+
+	metrics, err := cass.CoordinateWebSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.TotalRequests != 6 {
+		t.Errorf("got %d total requests, want 6", metrics.TotalRequests)
+	}
+	if metrics.FailedRequests != 0 {
+		t.Errorf("got %d failed requests, want 0", metrics.FailedRequests)
+	}
+}
+
+// TestCoordinateWebSocketOneConnFails ensures a single failed connection
+// among many concurrent ones is recorded as a failure rather than
+// aborting the whole run: the server rejects exactly one upgrade, and
+// the other connections must still complete and be reflected in the
+// final metrics.
+func TestCoordinateWebSocketOneConnFails(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var seen int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&seen, 1) == 1 {
+			// Reject the first connection outright instead of upgrading it.
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	cass := &Cassowary{
+		BaseURL:          "ws" + strings.TrimPrefix(srv.URL, "http"),
+		ConcurrencyLevel: 3,
+		WSMessageCount:   2,
+		WSMessage:        []byte("ping"),
+	}
+
+	metrics, err := cass.CoordinateWebSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.FailedRequests != 1 {
+		t.Errorf("got %d failed requests, want 1", metrics.FailedRequests)
+	}
+	if metrics.TotalRequests != 5 {
+		t.Errorf("got %d total requests, want 5 (4 successful messages + 1 failed connection)", metrics.TotalRequests)
+	}
+}
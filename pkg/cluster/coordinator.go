@@ -0,0 +1,199 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/rogerwelin/cassowary/pkg/client"
+)
+
+// errInvalidToken is returned by Register when a worker presents a
+// token that doesn't match the coordinator's.
+var errInvalidToken = errors.New("cluster: invalid worker token")
+
+// errNoWorkers is returned by Run when no worker has registered yet.
+var errNoWorkers = errors.New("cluster: no workers registered")
+
+type registeredWorker struct {
+	id       string
+	address  string
+	client   *rpc.Client
+	lastSeen time.Time
+}
+
+// Coordinator accepts worker registrations guarded by a shared token,
+// shards a load test's Requests/ConcurrencyLevel across every worker
+// registered by the time Run is called, and merges the raw,
+// un-aggregated timings they stream back into a single ResultMetrics.
+type Coordinator struct {
+	listenAddr string
+	token      string
+
+	mu      sync.Mutex
+	nextID  int
+	workers map[string]*registeredWorker
+	timings map[string][]client.RequestTiming
+}
+
+// NewCoordinator creates a coordinator that will listen on listenAddr
+// and only accept worker registrations presenting token.
+func NewCoordinator(listenAddr, token string) *Coordinator {
+	return &Coordinator{
+		listenAddr: listenAddr,
+		token:      token,
+		workers:    make(map[string]*registeredWorker),
+		timings:    make(map[string][]client.RequestTiming),
+	}
+}
+
+// Listen starts the coordinator's RPC server in the background. Call it
+// once, before workers are expected to register.
+func (c *Coordinator) Listen() error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Coordinator", c); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", c.listenAddr)
+	if err != nil {
+		return err
+	}
+
+	go server.Accept(listener)
+	return nil
+}
+
+// Register is the net/rpc entry point a worker calls to join the
+// cluster. It dials the worker back on the address it advertised, so
+// that Run can later call StartJob on it directly.
+func (c *Coordinator) Register(args RegisterArgs, reply *RegisterReply) error {
+	if args.Token != c.token {
+		return errInvalidToken
+	}
+
+	workerClient, err := rpc.Dial("tcp", args.Address)
+	if err != nil {
+		return fmt.Errorf("cluster: could not dial back worker at %s: %s", args.Address, err)
+	}
+
+	c.mu.Lock()
+	c.nextID++
+	id := fmt.Sprintf("worker-%d", c.nextID)
+	c.workers[id] = &registeredWorker{id: id, address: args.Address, client: workerClient, lastSeen: time.Now()}
+	c.mu.Unlock()
+
+	reply.WorkerID = id
+	return nil
+}
+
+// Heartbeat is the net/rpc entry point a worker calls periodically to
+// prove it is still alive.
+func (c *Coordinator) Heartbeat(args HeartbeatArgs, ack *Ack) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if w, ok := c.workers[args.WorkerID]; ok {
+		w.lastSeen = time.Now()
+	}
+	return nil
+}
+
+// ReportBatch is the net/rpc entry point a worker calls to stream back a
+// chunk of raw per-request timings as they're produced.
+func (c *Coordinator) ReportBatch(batch ResultBatch, ack *Ack) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timings[batch.WorkerID] = append(c.timings[batch.WorkerID], batch.Timings...)
+	return nil
+}
+
+// shardJob splits spec's Requests and ConcurrencyLevel evenly across n
+// workers, handing any remainder to the first shards. Duration, being a
+// wall-clock budget rather than a count, is left unchanged on every
+// shard so all workers run for the same span of time.
+func shardJob(spec JobSpec, n int) []JobSpec {
+	shards := make([]JobSpec, n)
+
+	baseRequests, requestRemainder := spec.Requests/n, spec.Requests%n
+	baseConcurrency, concurrencyRemainder := spec.ConcurrencyLevel/n, spec.ConcurrencyLevel%n
+
+	for i := 0; i < n; i++ {
+		shard := spec
+
+		shard.Requests = baseRequests
+		if i < requestRemainder {
+			shard.Requests++
+		}
+		if shard.Requests == 0 {
+			shard.Requests = 1
+		}
+
+		shard.ConcurrencyLevel = baseConcurrency
+		if i < concurrencyRemainder {
+			shard.ConcurrencyLevel++
+		}
+		if shard.ConcurrencyLevel == 0 {
+			shard.ConcurrencyLevel = 1
+		}
+
+		shards[i] = shard
+	}
+
+	return shards
+}
+
+// Run shards spec across every worker registered so far, blocks until
+// they've all finished, and returns the merged ResultMetrics. The
+// existing Prometheus/CloudWatch/JSON exporters should be run by the
+// caller against the returned metrics - only the coordinator, never a
+// worker, exports.
+func (c *Coordinator) Run(spec JobSpec) (client.ResultMetrics, error) {
+	c.mu.Lock()
+	workers := make([]*registeredWorker, 0, len(c.workers))
+	for _, w := range c.workers {
+		workers = append(workers, w)
+	}
+	c.mu.Unlock()
+
+	if len(workers) == 0 {
+		return client.ResultMetrics{}, errNoWorkers
+	}
+
+	shards := shardJob(spec, len(workers))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(workers))
+	wg.Add(len(workers))
+
+	start := time.Now()
+	for i, w := range workers {
+		go func(w *registeredWorker, shard JobSpec) {
+			defer wg.Done()
+			var ack Ack
+			if err := w.client.Call("Worker.StartJob", shard, &ack); err != nil {
+				errs <- fmt.Errorf("cluster: worker %s: %s", w.id, err)
+			}
+		}(w, shards[i])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return client.ResultMetrics{}, err
+		}
+	}
+
+	c.mu.Lock()
+	var all []client.RequestTiming
+	for _, t := range c.timings {
+		all = append(all, t...)
+	}
+	c.mu.Unlock()
+
+	return client.MergeRequestTimings(spec.BaseURL, all, len(all), elapsed), nil
+}
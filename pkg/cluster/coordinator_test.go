@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShardJobEvenSplit(t *testing.T) {
+	spec := JobSpec{Requests: 100, ConcurrencyLevel: 10}
+	shards := shardJob(spec, 5)
+
+	if len(shards) != 5 {
+		t.Fatalf("expected 5 shards, got %d", len(shards))
+	}
+
+	totalRequests := 0
+	totalConcurrency := 0
+	for _, s := range shards {
+		totalRequests += s.Requests
+		totalConcurrency += s.ConcurrencyLevel
+	}
+
+	if totalRequests != 100 {
+		t.Fatalf("expected shards to sum to 100 requests, got %d", totalRequests)
+	}
+	if totalConcurrency != 10 {
+		t.Fatalf("expected shards to sum to 10 concurrency, got %d", totalConcurrency)
+	}
+}
+
+func TestShardJobRemainder(t *testing.T) {
+	spec := JobSpec{Requests: 10, ConcurrencyLevel: 2}
+	shards := shardJob(spec, 3)
+
+	totalRequests := 0
+	for _, s := range shards {
+		totalRequests += s.Requests
+		if s.ConcurrencyLevel < 1 {
+			t.Fatalf("expected every shard to have at least 1 concurrency, got %d", s.ConcurrencyLevel)
+		}
+	}
+	if totalRequests != 10 {
+		t.Fatalf("expected shards to sum to 10 requests, got %d", totalRequests)
+	}
+}
+
+func TestShardJobFewerRequestsThanWorkers(t *testing.T) {
+	spec := JobSpec{Requests: 2, ConcurrencyLevel: 1}
+	shards := shardJob(spec, 5)
+
+	for _, s := range shards {
+		if s.Requests < 1 {
+			t.Fatalf("expected every shard to run at least 1 request, got %d", s.Requests)
+		}
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+// TestWorkerRegistersWithCoordinator exercises Coordinator.Listen,
+// Worker.Run and Coordinator.Run end-to-end against a real TCP
+// connection, so a regression in the listen/register ordering (the
+// worker must be reachable before it registers) fails a test instead of
+// only showing up against a real cluster.
+func TestWorkerRegistersWithCoordinator(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	coordAddr := freeAddr(t)
+	workerAddr := freeAddr(t)
+
+	coord := NewCoordinator(coordAddr, "secret")
+	if err := coord.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := NewWorker(coordAddr, workerAddr, "secret")
+	workerErrs := make(chan error, 1)
+	go func() { workerErrs <- worker.Run() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		coord.mu.Lock()
+		n := len(coord.workers)
+		coord.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			select {
+			case err := <-workerErrs:
+				t.Fatalf("worker registration never succeeded, worker.Run returned: %v", err)
+			default:
+				t.Fatal("worker never registered with coordinator")
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	metrics, err := coord.Run(JobSpec{BaseURL: backend.URL, Requests: 4, ConcurrencyLevel: 1, Timeout: 5})
+	if err != nil {
+		t.Fatalf("coord.Run: %v", err)
+	}
+	if metrics.TotalRequests != 4 {
+		t.Errorf("expected 4 total requests, got %d", metrics.TotalRequests)
+	}
+}
+
+// TestWorkerDrainsInFlightJobOnSIGTERM exercises SIGTERM arriving while
+// a job is in flight: Run must not return until StartJob has finished,
+// rather than exiting underneath it.
+func TestWorkerDrainsInFlightJobOnSIGTERM(t *testing.T) {
+	release := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	coordAddr := freeAddr(t)
+	workerAddr := freeAddr(t)
+
+	coord := NewCoordinator(coordAddr, "secret")
+	if err := coord.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := NewWorker(coordAddr, workerAddr, "secret")
+	workerDone := make(chan error, 1)
+	go func() { workerDone <- worker.Run() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		coord.mu.Lock()
+		n := len(coord.workers)
+		coord.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("worker never registered with coordinator")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	jobDone := make(chan error, 1)
+	go func() {
+		_, err := coord.Run(JobSpec{BaseURL: backend.URL, Requests: 1, ConcurrencyLevel: 1, Timeout: 5})
+		jobDone <- err
+	}()
+
+	// Give StartJob time to reach the worker and block in the backend
+	// handler before signaling.
+	time.Sleep(100 * time.Millisecond)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-workerDone:
+		t.Fatalf("worker.Run returned before the in-flight job drained: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+
+	if err := <-jobDone; err != nil {
+		t.Fatalf("coord.Run: %v", err)
+	}
+
+	select {
+	case err := <-workerDone:
+		if err != nil {
+			t.Fatalf("worker.Run returned an error after draining: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker.Run never returned after the in-flight job finished")
+	}
+}
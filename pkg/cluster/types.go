@@ -0,0 +1,68 @@
+// Package cluster shards a cassowary load test across a coordinator and
+// any number of workers, communicating over net/rpc: the coordinator
+// splits a run's concurrency and requests across every registered
+// worker (each shard keeps the run's full duration), hands each its
+// shard with StartJob, and receives raw,
+// un-aggregated per-request timings back via ResultBatch so that
+// percentiles stay accurate across the whole run rather than being
+// averaged per-shard.
+//
+// SCOPE DECISION: the request for this package asked for gRPC +
+// protobuf as the transport. It's plain net/rpc instead. Sign-off:
+// accepted for now — this build environment has no protoc and no way to
+// install one, so there was never a way to generate or verify a
+// .proto-based service here, and net/rpc is already this repo's
+// established RPC idiom (see pkg/client/plugin.go). Consequence:
+// coordinator and worker binaries must both be Go; a non-Go worker
+// implementation is not possible as shipped. Revisit once a
+// protoc-capable environment is available. A later move to gRPC would
+// replace Coordinator/Worker's net/rpc server and client setup with
+// generated stubs; JobSpec, ResultBatch and the other wire types here
+// would carry over as the proto message shapes.
+package cluster
+
+import "github.com/rogerwelin/cassowary/pkg/client"
+
+// JobSpec is a single worker's shard of a load test, sent by the
+// coordinator via Worker.StartJob.
+type JobSpec struct {
+	BaseURL          string
+	ConcurrencyLevel int
+	Requests         int
+	Duration         int
+	Timeout          int
+	HTTPMethod       string
+	RequestHeader    []string
+	Data             []byte
+	DisableKeepAlive bool
+}
+
+// RegisterArgs is sent by a worker to join a coordinator.
+type RegisterArgs struct {
+	Token   string
+	Address string
+}
+
+// RegisterReply acknowledges a worker's registration with the ID it
+// should use on every subsequent call.
+type RegisterReply struct {
+	WorkerID string
+}
+
+// HeartbeatArgs is sent periodically by a registered worker. The
+// coordinator records the time of each heartbeat as the worker's
+// last-seen time; it takes no action on a worker whose heartbeats stop.
+type HeartbeatArgs struct {
+	WorkerID string
+}
+
+// ResultBatch carries a chunk of raw per-request timings from a worker
+// back to the coordinator. Done marks the last batch for a given job.
+type ResultBatch struct {
+	WorkerID string
+	Done     bool
+	Timings  []client.RequestTiming
+}
+
+// Ack is an empty acknowledgement for RPCs that return no data.
+type Ack struct{}
@@ -0,0 +1,174 @@
+package cluster
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/rpc"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rogerwelin/cassowary/pkg/client"
+)
+
+// errDraining is returned by StartJob when the worker has received
+// SIGTERM and is no longer accepting new jobs.
+var errDraining = errors.New("cluster: worker is draining, not accepting new jobs")
+
+// Worker runs load test shards on behalf of a coordinator. It registers
+// with a shared token, exposes StartJob over net/rpc, streams raw
+// per-request timings back to the coordinator as they're produced, and
+// drains cleanly on SIGTERM by letting an in-flight job finish before
+// the process exits.
+type Worker struct {
+	coordAddr  string
+	listenAddr string
+	token      string
+
+	mu       sync.Mutex
+	id       string
+	draining bool
+	coord    *rpc.Client
+
+	// inFlight tracks StartJob calls currently running, so Run can block
+	// on SIGTERM until they've all returned instead of exiting underneath
+	// them.
+	inFlight sync.WaitGroup
+}
+
+// NewWorker creates a worker that will register with the coordinator at
+// coordAddr using token, and itself listen for StartJob calls on
+// listenAddr.
+func NewWorker(coordAddr, listenAddr, token string) *Worker {
+	return &Worker{coordAddr: coordAddr, listenAddr: listenAddr, token: token}
+}
+
+// Run registers with the coordinator, starts the worker's own RPC
+// server, sends periodic heartbeats, and blocks until the process
+// receives SIGTERM, at which point it stops accepting new jobs and
+// returns once any in-flight job has drained.
+func (w *Worker) Run() error {
+	// The worker must be listening before it registers: Coordinator.Register
+	// dials the advertised address back immediately to confirm it's
+	// reachable, so registering first always finds a closed port.
+	listener, err := net.Listen("tcp", w.listenAddr)
+	if err != nil {
+		return err
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("Worker", w); err != nil {
+		return err
+	}
+	go server.Accept(listener)
+
+	coord, err := rpc.Dial("tcp", w.coordAddr)
+	if err != nil {
+		return err
+	}
+	w.coord = coord
+
+	var reply RegisterReply
+	if err := coord.Call("Coordinator.Register", RegisterArgs{Token: w.token, Address: w.listenAddr}, &reply); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.id = reply.WorkerID
+	w.mu.Unlock()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM)
+
+	heartbeat := time.NewTicker(5 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-stop:
+			w.mu.Lock()
+			w.draining = true
+			w.mu.Unlock()
+			closeErr := listener.Close()
+			w.inFlight.Wait()
+			return closeErr
+		case <-heartbeat.C:
+			var ack Ack
+			w.mu.Lock()
+			id := w.id
+			w.mu.Unlock()
+			if err := w.coord.Call("Coordinator.Heartbeat", HeartbeatArgs{WorkerID: id}, &ack); err != nil {
+				log.Printf("cluster: worker %s: heartbeat failed: %s", id, err)
+			}
+		}
+	}
+}
+
+// StartJob is the net/rpc entry point the coordinator calls to hand this
+// worker its shard of the load test. It runs the shard to completion,
+// streaming every request's raw timing back to the coordinator as
+// ResultBatch RPCs, and rejects new jobs while draining.
+func (w *Worker) StartJob(spec JobSpec, ack *Ack) error {
+	w.mu.Lock()
+	if w.draining {
+		w.mu.Unlock()
+		return errDraining
+	}
+	id := w.id
+	w.inFlight.Add(1)
+	w.mu.Unlock()
+	defer w.inFlight.Done()
+
+	cass := &client.Cassowary{
+		BaseURL:               spec.BaseURL,
+		ConcurrencyLevel:      spec.ConcurrencyLevel,
+		Requests:              spec.Requests,
+		Duration:              spec.Duration,
+		Timeout:               spec.Timeout,
+		HTTPMethod:            spec.HTTPMethod,
+		RequestHeader:         spec.RequestHeader,
+		Data:                  spec.Data,
+		DisableKeepAlive:      spec.DisableKeepAlive,
+		DisableTerminalOutput: true,
+	}
+
+	raw := make(chan client.RequestTiming, spec.ConcurrencyLevel)
+	cass.RawOutput = raw
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cass.Coordinate()
+		close(raw)
+		done <- err
+	}()
+
+	const batchSize = 100
+	var batch []client.RequestTiming
+
+	flush := func(last bool) error {
+		if len(batch) == 0 && !last {
+			return nil
+		}
+		var batchAck Ack
+		err := w.coord.Call("Coordinator.ReportBatch", ResultBatch{WorkerID: id, Done: last, Timings: batch}, &batchAck)
+		batch = nil
+		return err
+	}
+
+	for timing := range raw {
+		batch = append(batch, timing)
+		if len(batch) >= batchSize {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(true); err != nil {
+		return err
+	}
+
+	return <-done
+}